@@ -0,0 +1,132 @@
+package ogle
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Input is a record read by ReadInputs.
+type Input struct {
+	// Line is the line number of the record, starting at 1.
+	Line int
+
+	// Value is the ID or URL of the resource.
+	Value string
+
+	// Fields holds all values of a JSON record. It is nil for plain lines.
+	Fields map[string]interface{}
+
+	// Err is set if the line could not be parsed.
+	Err error
+}
+
+// String returns the first of the named fields that holds a string.
+func (in Input) String(names ...string) (string, bool) {
+	for _, name := range names {
+		if s, ok := in.Fields[name].(string); ok {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Bool returns the first of the named fields that holds a boolean or a
+// string that parses as one, like "true".
+func (in Input) Bool(names ...string) (bool, bool) {
+	for _, name := range names {
+		switch v := in.Fields[name].(type) {
+		case bool:
+			return v, true
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, true
+			}
+		}
+	}
+	return false, false
+}
+
+// Strings returns the first of the named fields that holds a list of strings
+// or a comma separated string.
+func (in Input) Strings(names ...string) ([]string, bool) {
+	for _, name := range names {
+		switch v := in.Fields[name].(type) {
+		case string:
+			list := make([]string, 0)
+			for _, s := range strings.Split(v, ",") {
+				list = append(list, strings.TrimSpace(s))
+			}
+			return list, true
+		case []interface{}:
+			list := make([]string, 0, len(v))
+			for _, s := range v {
+				list = append(list, fmt.Sprint(s))
+			}
+			return list, true
+		}
+	}
+	return nil, false
+}
+
+// maxInputLine is the longest line accepted by ReadInputs.
+const maxInputLine = 1024 * 1024
+
+// ReadInputs reads records from r, one per line, calling f with batches of up
+// to size records. A line is either an ID or URL, or a JSON object (JSON
+// Lines) with the ID or URL in the key field, or in the "id" or "url" fields.
+// Empty lines and lines starting with '#' are skipped.
+//
+// Lines that cannot be parsed are passed to f with Err set, so they can be
+// reported along with the other results.
+func ReadInputs(r io.Reader, key string, size int, f func([]Input) error) error {
+	if size < 1 {
+		size = 1
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), maxInputLine)
+	batch := make([]Input, 0, size)
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		batch = append(batch, parseInput(line, text, key))
+		if len(batch) == size {
+			if err := f(batch); err != nil {
+				return err
+			}
+			batch = make([]Input, 0, size)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("ogle: error reading input at line %d: %v", line+1, err)
+	}
+	if len(batch) > 0 {
+		return f(batch)
+	}
+	return nil
+}
+
+func parseInput(line int, text, key string) Input {
+	in := Input{Line: line, Value: text}
+	if !strings.HasPrefix(text, "{") {
+		return in
+	}
+	if err := json.Unmarshal([]byte(text), &in.Fields); err != nil {
+		in.Err = fmt.Errorf("ogle: invalid JSON record at line %d: %v", line, err)
+		return in
+	}
+	v, ok := in.String(key, "id", "url")
+	if !ok || v == "" {
+		in.Err = fmt.Errorf("ogle: no %q, \"id\" or \"url\" field in record at line %d", key, line)
+		return in
+	}
+	in.Value = v
+	return in
+}
@@ -0,0 +1,267 @@
+package ogle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CacheDir returns the directory where cached data for the given API is
+// stored. The directory is not created by this function.
+func CacheDir(api string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(os.Getenv("HOME"), "Library", "Caches", "ogle", api)
+	case "linux", "freebsd":
+		return filepath.Join(os.Getenv("HOME"), ".cache", "ogle", api)
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "ogle", api)
+	}
+	return filepath.Join(".", "ogle-cache", api)
+}
+
+// accountCacheDir returns the directory where data that belongs to the
+// account selected with SetAccount, like checkpoints and upload sessions, is
+// stored. The default account uses CacheDir itself.
+func accountCacheDir(api string) string {
+	if account := accountFileName(api); account != "" {
+		return filepath.Join(CacheDir(api), "accounts", account)
+	}
+	return CacheDir(api)
+}
+
+// ClearCache removes all cached responses for the given API.
+func ClearCache(api string) error {
+	return os.RemoveAll(filepath.Join(CacheDir(api), "http"))
+}
+
+// CacheTransport is an http.RoundTripper that stores GET responses on disk and
+// uses their ETags to issue conditional requests. When the server replies with
+// 304 Not Modified, the cached response is returned to the caller as if it was
+// freshly downloaded.
+type CacheTransport struct {
+	// Base is the transport used to issue the actual requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Dir is the directory where responses are stored.
+	Dir string
+
+	// Account is part of the cache key, so the responses for requests like
+	// "mine=true" are not shared between accounts.
+	Account string
+
+	// TTL is how long a cached response is served without revalidation. When
+	// zero, every request is revalidated with the server using If-None-Match.
+	TTL time.Duration
+
+	// MaxSize is the maximum size in bytes of all cached responses. Oldest
+	// entries are removed when the limit is exceeded. Zero means no limit.
+	MaxSize int64
+}
+
+// NewCacheTransport initializes a CacheTransport that stores responses for
+// the given API in its CacheDir, wrapping the provided base transport.
+func NewCacheTransport(api string, base http.RoundTripper) *CacheTransport {
+	return &CacheTransport{
+		Base:    base,
+		Dir:     filepath.Join(CacheDir(api), "http"),
+		Account: Account(api),
+	}
+}
+
+type cacheEntry struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	StoredAt   time.Time
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Only plain GET requests are cached. Requests that are already
+	// conditional are handled by the caller.
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return t.base().RoundTrip(req)
+	}
+
+	filename := t.filename(req)
+	entry, err := t.load(filename)
+	if err != nil {
+		return t.fetch(req, filename)
+	}
+	if t.TTL > 0 && time.Since(entry.StoredAt) < t.TTL {
+		return entry.response(req), nil
+	}
+	etag := entry.Header.Get("Etag")
+	if etag == "" {
+		return t.fetch(req, filename)
+	}
+
+	creq := req.Clone(req.Context())
+	creq.Header.Set("If-None-Match", etag)
+	resp, err := t.base().RoundTrip(creq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusNotModified {
+		return t.store(req, resp, filename)
+	}
+	resp.Body.Close()
+	entry.StoredAt = time.Now()
+	if err := t.save(filename, entry); err != nil {
+		return nil, err
+	}
+	return entry.response(req), nil
+}
+
+func (t *CacheTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+func (t *CacheTransport) filename(req *http.Request) string {
+	key := req.Method + " " + req.URL.String()
+	if t.Account != "" {
+		key = t.Account + " " + key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(t.Dir, hex.EncodeToString(sum[:]))
+}
+
+func (t *CacheTransport) fetch(req *http.Request, filename string) (*http.Response, error) {
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return t.store(req, resp, filename)
+}
+
+// store saves successful responses to the cache file, returning a response
+// with an equivalent body to the caller.
+func (t *CacheTransport) store(req *http.Request, resp *http.Response, filename string) (*http.Response, error) {
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &cacheEntry{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		StoredAt:   time.Now(),
+	}
+	if err := t.save(filename, entry); err != nil {
+		return nil, err
+	}
+	if err := t.prune(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *CacheTransport) load(filename string) (*cacheEntry, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	e := new(cacheEntry)
+	if err := gob.NewDecoder(f).Decode(e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (t *CacheTransport) save(filename string, e *cacheEntry) error {
+	if err := os.MkdirAll(t.Dir, 0700); err != nil {
+		return fmt.Errorf("ogle: unable to create cache dir: %v", err)
+	}
+	// Entries are written to a temporary file and renamed, so concurrent
+	// readers never see a partial entry.
+	f, err := os.CreateTemp(t.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if err := gob.NewEncoder(f).Encode(e); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// prune removes the least recently stored entries until the cache fits in
+// MaxSize.
+func (t *CacheTransport) prune() error {
+	if t.MaxSize <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(t.Dir)
+	if err != nil {
+		return err
+	}
+	infos := make([]fs.FileInfo, 0, len(entries))
+	var total int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			continue
+		}
+		infos = append(infos, info)
+		total += info.Size()
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ModTime().Before(infos[j].ModTime())
+	})
+	for _, info := range infos {
+		if total <= t.MaxSize {
+			break
+		}
+		if err := os.Remove(filepath.Join(t.Dir, info.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= info.Size()
+	}
+	return nil
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
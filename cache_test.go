@@ -0,0 +1,263 @@
+package ogle
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheTransportAccounts(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Etag", `"v1"`)
+		fmt.Fprintf(w, "response %d", calls)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	get := func(account string) string {
+		t.Helper()
+		c := &http.Client{Transport: &CacheTransport{Dir: dir, Account: account, TTL: 1 << 40}}
+		resp, err := c.Get(srv.URL + "/channels?mine=true")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+	for _, tt := range []struct{ account, want string }{
+		{"", "response 1"},
+		{"work", "response 2"},
+		{"", "response 1"},
+		{"work", "response 2"},
+		{"home", "response 3"},
+	} {
+		if got := get(tt.account); got != tt.want {
+			t.Errorf("account %q: got %q, want %q", tt.account, got, tt.want)
+		}
+	}
+}
+
+func TestAccountFileName(t *testing.T) {
+	tests := []struct {
+		account, want string
+	}{
+		{"", ""},
+		{"work", "work"},
+		{"me+yt@example.com", "me+yt@example.com"},
+		{"../../etc/passwd", "_._.._etc_passwd"},
+		{`a\b:c d`, "a_b_c_d"},
+		{".hidden", "_hidden"},
+	}
+	defer SetAccount("test", "")
+	for _, tt := range tests {
+		SetAccount("test", tt.account)
+		if got := accountFileName("test"); got != tt.want {
+			t.Errorf("accountFileName(%q) = %q, want %q", tt.account, got, tt.want)
+		}
+		dir := accountCacheDir("test")
+		if rel, err := filepath.Rel(CacheDir("test"), dir); err != nil || strings.HasPrefix(rel, "..") {
+			t.Errorf("accountCacheDir with account %q is %q, outside of the cache dir", tt.account, dir)
+		}
+	}
+}
+
+// cacheStep is a request made through the CacheTransport.
+type cacheStep struct {
+	method string
+	path   string
+	ttl    time.Duration
+	update bool   // change the server content before the request
+	body   string // returned to the caller
+	sent   string // request seen by the server, empty if none
+}
+
+func TestCacheTransport(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []cacheStep
+	}{
+		{
+			name: "revalidated with the ETag",
+			steps: []cacheStep{
+				{path: "/etag", body: "v1 1", sent: `GET /etag`},
+				{path: "/etag", body: "v1 1", sent: `GET /etag If-None-Match:"v1"`},
+				{path: "/etag", body: "v1 1", sent: `GET /etag If-None-Match:"v1"`},
+			},
+		},
+		{
+			name: "changed content replaces the entry",
+			steps: []cacheStep{
+				{path: "/etag", body: "v1 1", sent: `GET /etag`},
+				{path: "/etag", update: true, body: "v2 2", sent: `GET /etag If-None-Match:"v1"`},
+				{path: "/etag", body: "v2 2", sent: `GET /etag If-None-Match:"v2"`},
+			},
+		},
+		{
+			name: "fresh entries are served without requests",
+			steps: []cacheStep{
+				{path: "/etag", ttl: time.Hour, body: "v1 1", sent: `GET /etag`},
+				{path: "/etag", ttl: time.Hour, update: true, body: "v1 1"},
+				{path: "/etag", ttl: time.Nanosecond, body: "v2 2", sent: `GET /etag If-None-Match:"v1"`},
+			},
+		},
+		{
+			name: "responses without ETag are fetched again",
+			steps: []cacheStep{
+				{path: "/plain", body: "plain 1", sent: `GET /plain`},
+				{path: "/plain", body: "plain 2", sent: `GET /plain`},
+				{path: "/plain", ttl: time.Hour, body: "plain 2"},
+			},
+		},
+		{
+			name: "errors are not cached",
+			steps: []cacheStep{
+				{path: "/missing", body: "missing 1", sent: `GET /missing`},
+				{path: "/missing", ttl: time.Hour, body: "missing 2", sent: `GET /missing`},
+			},
+		},
+		{
+			name: "only GET requests are cached",
+			steps: []cacheStep{
+				{method: "POST", path: "/etag", body: "v1 1", sent: `POST /etag`},
+				{method: "POST", path: "/etag", ttl: time.Hour, body: "v1 2", sent: `POST /etag`},
+				{path: "/etag", ttl: time.Hour, body: "v1 3", sent: `GET /etag`},
+				{method: "DELETE", path: "/etag", ttl: time.Hour, body: "v1 4", sent: `DELETE /etag`},
+			},
+		},
+		{
+			name: "query strings are part of the key",
+			steps: []cacheStep{
+				{path: "/etag?page=1", ttl: time.Hour, body: "v1 1", sent: `GET /etag`},
+				{path: "/etag?page=2", ttl: time.Hour, body: "v1 2", sent: `GET /etag`},
+				{path: "/etag?page=1", ttl: time.Hour, body: "v1 1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, calls := 1, 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				etag := fmt.Sprintf(`"v%d"`, version)
+				switch r.URL.Path {
+				case "/etag":
+					w.Header().Set("Etag", etag)
+					if r.Header.Get("If-None-Match") == etag {
+						w.WriteHeader(http.StatusNotModified)
+						return
+					}
+					fmt.Fprintf(w, "v%d %d", version, calls)
+				case "/plain":
+					fmt.Fprintf(w, "plain %d", calls)
+				default:
+					w.Header().Set("Etag", etag)
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprintf(w, "missing %d", calls)
+				}
+			}))
+			defer srv.Close()
+
+			dir := t.TempDir()
+			for i, step := range tt.steps {
+				if step.update {
+					version++
+				}
+				var sent string
+				base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					sent = req.Method + " " + req.URL.Path
+					if inm := req.Header.Get("If-None-Match"); inm != "" {
+						sent += " If-None-Match:" + inm
+					}
+					return http.DefaultTransport.RoundTrip(req)
+				})
+				c := &http.Client{Transport: &CacheTransport{Base: base, Dir: dir, TTL: step.ttl}}
+				method := step.method
+				if method == "" {
+					method = "GET"
+				}
+				req, _ := http.NewRequest(method, srv.URL+step.path, nil)
+				resp, err := c.Do(req)
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				b, _ := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if string(b) != step.body {
+					t.Errorf("step %d: body %q, want %q", i, b, step.body)
+				}
+				if sent != step.sent {
+					t.Errorf("step %d: server got %q, want %q", i, sent, step.sent)
+				}
+				if resp.StatusCode == http.StatusNotModified {
+					t.Errorf("step %d: 304 returned to the caller", i)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheTransportMaxSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Etag", `"1"`)
+		w.Write(bytes.Repeat([]byte("x"), 1000))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	ct := &CacheTransport{Dir: dir, TTL: time.Hour, MaxSize: 3500}
+	c := &http.Client{Transport: ct}
+	for i := 0; i < 6; i++ {
+		resp, err := c.Get(fmt.Sprintf("%s/item/%d", srv.URL, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		// Entries are pruned by age, so keep their times apart.
+		time.Sleep(10 * time.Millisecond)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for _, e := range entries {
+		info, _ := e.Info()
+		total += info.Size()
+		if strings.HasPrefix(e.Name(), ".tmp-") {
+			t.Errorf("temporary file %s left in the cache", e.Name())
+		}
+	}
+	if total > ct.MaxSize || len(entries) == 0 {
+		t.Errorf("cache has %d entries with %d bytes, want at most %d bytes", len(entries), total, ct.MaxSize)
+	}
+	// The newest entry is kept and the oldest removed.
+	for i, want := range map[int]bool{0: false, 5: true} {
+		_, err := ct.load(ct.filename(mustRequest(t, fmt.Sprintf("%s/item/%d", srv.URL, i))))
+		if got := err == nil; got != want {
+			t.Errorf("entry %d cached = %v, want %v", i, got, want)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func mustRequest(t *testing.T, url string) *http.Request {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
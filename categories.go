@@ -0,0 +1,137 @@
+package ogle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/youtube/v3"
+)
+
+// DefaultRegion is the region used to list video categories when none is
+// configured.
+const DefaultRegion = "US"
+
+// categoryTTL is how long a cached category table is used before it is
+// fetched again.
+const categoryTTL = 30 * 24 * time.Hour
+
+// Category is a video category available in a region.
+type Category struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+
+	// Assignable reports if videos can be assigned to the category.
+	Assignable bool `json:"assignable"`
+}
+
+// CategoryTable holds the video categories of a region.
+type CategoryTable struct {
+	Region     string     `json:"region"`
+	FetchedAt  time.Time  `json:"fetchedAt"`
+	Categories []Category `json:"categories"`
+}
+
+// FetchCategories lists the video categories of the region.
+func FetchCategories(ctx context.Context, yt *youtube.Service, region string) (*CategoryTable, error) {
+	resp, err := yt.VideoCategories.List([]string{"snippet"}).RegionCode(region).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	t := &CategoryTable{Region: region, FetchedAt: time.Now()}
+	for _, c := range resp.Items {
+		if c.Snippet == nil {
+			continue
+		}
+		t.Categories = append(t.Categories, Category{ID: c.Id, Title: c.Snippet.Title, Assignable: c.Snippet.Assignable})
+	}
+	// IDs are numeric strings, so shorter IDs come first, like 2 before 10.
+	sort.SliceStable(t.Categories, func(i, j int) bool {
+		return len(t.Categories[i].ID) < len(t.Categories[j].ID) ||
+			len(t.Categories[i].ID) == len(t.Categories[j].ID) && t.Categories[i].ID < t.Categories[j].ID
+	})
+	return t, nil
+}
+
+// CategoryFile returns the name of the file where the categories of the
+// region are cached.
+func CategoryFile(api, region string) string {
+	return filepath.Join(CacheDir(api), "categories", strings.ToUpper(region)+".json")
+}
+
+// LoadCategories reads the category table cached in filename, returning nil
+// if there is none or it is older than 30 days.
+func LoadCategories(filename string) (*CategoryTable, error) {
+	b, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := new(CategoryTable)
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, fmt.Errorf("ogle: invalid category cache %v: %v", filename, err)
+	}
+	if time.Since(t.FetchedAt) > categoryTTL {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// SaveCategories writes the category table to filename.
+func SaveCategories(filename string, t *CategoryTable) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("ogle: unable to create category cache dir: %v", err)
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// categoryKey normalizes a category name for comparison, ignoring case,
+// spaces and punctuation, so "Film & Animation" matches "film-and-animation".
+func categoryKey(s string) string {
+	s = strings.ReplaceAll(strings.ToLower(s), "&", "and")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// Lookup finds the category with the given ID or name. Names are compared
+// ignoring case, spaces and punctuation. Only assignable categories are
+// returned.
+func (t *CategoryTable) Lookup(s string) (Category, error) {
+	key := categoryKey(s)
+	for _, c := range t.Categories {
+		if c.ID != s && categoryKey(c.Title) != key {
+			continue
+		}
+		if !c.Assignable {
+			return c, UsageError("category %q (%s) cannot be assigned to videos", c.Title, c.ID)
+		}
+		return c, nil
+	}
+	names := make([]string, 0, len(t.Categories))
+	for _, c := range t.Categories {
+		if c.Assignable {
+			names = append(names, c.Title)
+		}
+	}
+	return Category{}, UsageError("unknown category %q in region %s, use one of: %s", s, t.Region, strings.Join(names, ", "))
+}
@@ -0,0 +1,153 @@
+package ogle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/option"
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestCategoryLookup(t *testing.T) {
+	table := &CategoryTable{
+		Region: "US",
+		Categories: []Category{
+			{ID: "1", Title: "Film & Animation", Assignable: true},
+			{ID: "2", Title: "Autos & Vehicles", Assignable: true},
+			{ID: "18", Title: "Short Movies"},
+			{ID: "22", Title: "People & Blogs", Assignable: true},
+			{ID: "28", Title: "Science & Technology", Assignable: true},
+		},
+	}
+	tests := []struct {
+		in   string
+		want string
+		err  string
+	}{
+		{in: "22", want: "22"},
+		{in: "Film & Animation", want: "1"},
+		{in: "film-and-animation", want: "1"},
+		{in: "FILM AND ANIMATION", want: "1"},
+		{in: "film&animation", want: "1"},
+		{in: "science_and_technology", want: "28"},
+		{in: "People and Blogs!", want: "22"},
+		{in: "Short Movies", err: `category "Short Movies" (18) cannot be assigned to videos`},
+		{in: "18", err: "cannot be assigned"},
+		{in: "Gaming", err: `unknown category "Gaming" in region US, use one of: Film & Animation, Autos & Vehicles, People & Blogs, Science & Technology`},
+		{in: "2 ", err: "unknown category"},
+		{in: "", err: "unknown category"},
+	}
+	for _, tt := range tests {
+		c, err := table.Lookup(tt.in)
+		if tt.err != "" {
+			var f *Failure
+			if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.As(err, &f) || f.ExitCode != ExitUsage {
+				t.Errorf("Lookup(%q) = (%v, %v), want usage error containing %q", tt.in, c, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || c.ID != tt.want {
+			t.Errorf("Lookup(%q) = (%v, %v), want ID %s", tt.in, c, err, tt.want)
+		}
+	}
+}
+
+func TestCategoryKey(t *testing.T) {
+	tests := map[string]string{
+		"Film & Animation":   "filmandanimation",
+		"film-and-animation": "filmandanimation",
+		"Pets  &  Animals":   "petsandanimals",
+		"Música":             "música",
+		"":                   "",
+	}
+	for in, want := range tests {
+		if got := categoryKey(in); got != want {
+			t.Errorf("categoryKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoadCategories(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "categories", "US.json")
+
+	if got, err := LoadCategories(filename); got != nil || err != nil {
+		t.Fatalf("missing file: got (%v, %v), want (nil, nil)", got, err)
+	}
+
+	fresh := &CategoryTable{
+		Region:     "US",
+		FetchedAt:  time.Now().Add(-29 * 24 * time.Hour).Round(0),
+		Categories: []Category{{ID: "22", Title: "People & Blogs", Assignable: true}},
+	}
+	if err := SaveCategories(filename, fresh); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadCategories(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || !got.FetchedAt.Equal(fresh.FetchedAt) || !reflect.DeepEqual(got.Categories, fresh.Categories) {
+		t.Errorf("fresh table: got %+v, want %+v", got, fresh)
+	}
+
+	expired := *fresh
+	expired.FetchedAt = time.Now().Add(-31 * 24 * time.Hour)
+	if err := SaveCategories(filename, &expired); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadCategories(filename); got != nil || err != nil {
+		t.Errorf("expired table: got (%v, %v), want (nil, nil)", got, err)
+	}
+
+	if err := os.WriteFile(filename, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCategories(filename); err == nil || !strings.Contains(err.Error(), "invalid category cache") {
+		t.Errorf("invalid file: got %v, want an error", err)
+	}
+}
+
+func TestFetchCategories(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("regionCode"); got != "BR" {
+			t.Errorf("regionCode %q, want BR", got)
+		}
+		var items []string
+		for _, id := range []string{"10", "2", "1", "44", "15"} {
+			items = append(items, fmt.Sprintf(`{"id": %q, "snippet": {"title": "Category %s", "assignable": %v}}`, id, id, id != "44"))
+		}
+		items = append(items, `{"id": "99"}`)
+		fmt.Fprintf(w, `{"items": [%s]}`, strings.Join(items, ","))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	yt, err := youtube.NewService(ctx, option.WithEndpoint(srv.URL), option.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, err := FetchCategories(ctx, yt, "BR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, c := range table.Categories {
+		ids = append(ids, c.ID)
+	}
+	if want := []string{"1", "2", "10", "15", "44"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("category IDs %v, want %v", ids, want)
+	}
+	if table.Region != "BR" || table.Categories[4].Assignable {
+		t.Errorf("unexpected table %+v", table)
+	}
+}
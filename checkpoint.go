@@ -0,0 +1,79 @@
+package ogle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint records the progress of a long listing, so it can be resumed
+// after an interruption.
+type Checkpoint struct {
+	// Params identifies the listing parameters. A checkpoint is only valid
+	// for a listing with the same parameters.
+	Params string `json:"params"`
+
+	// Cursor is the position of the next item to be processed.
+	Cursor Cursor `json:"cursor"`
+
+	// Count is the number of items already processed.
+	Count int `json:"count"`
+
+	// UpdatedAt is when the checkpoint was last saved.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CheckpointFile returns the file name used to store the checkpoint of the
+// named listing for the given API and the account selected with SetAccount.
+func CheckpointFile(api, name string) string {
+	return filepath.Join(accountCacheDir(api), "checkpoints", name+".json")
+}
+
+// LoadCheckpoint reads the checkpoint file, returning nil if there is no
+// checkpoint. A checkpoint saved with different params is invalid: it is
+// removed and nil is returned.
+func LoadCheckpoint(filename, params string) (*Checkpoint, error) {
+	b, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := new(Checkpoint)
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("ogle: invalid checkpoint %v: %v", filename, err)
+	}
+	if c.Params != params {
+		return nil, RemoveCheckpoint(filename)
+	}
+	return c, nil
+}
+
+// SaveCheckpoint writes the checkpoint to the given file, replacing any
+// previous one.
+func SaveCheckpoint(filename string, c *Checkpoint) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("ogle: unable to create checkpoint dir: %v", err)
+	}
+	c.UpdatedAt = time.Now()
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// RemoveCheckpoint removes the checkpoint file, if it exists.
+func RemoveCheckpoint(filename string) error {
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
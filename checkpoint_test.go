@@ -0,0 +1,79 @@
+package ogle
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckpoint(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "checkpoints", "videos.json")
+
+	c, err := LoadCheckpoint(filename, "videos|channel=UC1")
+	if err != nil || c != nil {
+		t.Fatalf("LoadCheckpoint without a file = %v, %v, want nil", c, err)
+	}
+
+	before := time.Now()
+	saved := &Checkpoint{Params: "videos|channel=UC1", Cursor: Cursor{PageToken: "CDIQAA", Offset: 3}, Count: 53}
+	if err := SaveCheckpoint(filename, saved); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+	if saved.UpdatedAt.Before(before) {
+		t.Errorf("UpdatedAt = %v, want after %v", saved.UpdatedAt, before)
+	}
+	got, err := LoadCheckpoint(filename, "videos|channel=UC1")
+	if err != nil {
+		t.Fatalf("LoadCheckpoint: %v", err)
+	}
+	if !got.UpdatedAt.Equal(saved.UpdatedAt) {
+		t.Errorf("UpdatedAt = %v, want %v", got.UpdatedAt, saved.UpdatedAt)
+	}
+	got.UpdatedAt = saved.UpdatedAt
+	if !reflect.DeepEqual(got, saved) {
+		t.Errorf("LoadCheckpoint = %+v, want %+v", got, saved)
+	}
+
+	// Saving again replaces the file atomically, leaving no temporary file.
+	saved.Count, saved.Cursor = 103, Cursor{PageToken: "CGQQAA"}
+	if err := SaveCheckpoint(filename, saved); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "videos.json" {
+		t.Errorf("checkpoint dir has %v, want only videos.json", entries)
+	}
+	if got, err = LoadCheckpoint(filename, "videos|channel=UC1"); err != nil || got.Count != 103 || got.Cursor.PageToken != "CGQQAA" {
+		t.Errorf("LoadCheckpoint after update = %+v, %v", got, err)
+	}
+
+	// A checkpoint of another listing is discarded.
+	got, err = LoadCheckpoint(filename, "videos|channel=UC2")
+	if err != nil || got != nil {
+		t.Errorf("LoadCheckpoint with other params = %+v, %v, want nil", got, err)
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("checkpoint with other params was not removed: %v", err)
+	}
+
+	if err := RemoveCheckpoint(filename); err != nil {
+		t.Errorf("RemoveCheckpoint of a missing file: %v", err)
+	}
+}
+
+func TestLoadCheckpointInvalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "broken.json")
+	if err := os.WriteFile(filename, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadCheckpoint(filename, "x")
+	if err == nil || !strings.Contains(err.Error(), "invalid checkpoint") {
+		t.Errorf("LoadCheckpoint = %v, want an invalid checkpoint error", err)
+	}
+}
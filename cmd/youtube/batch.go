@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ronoaldo/ogle"
+	"golang.org/x/net/context"
+)
+
+// stdinArg is the flag value that reads the IDs from the standard input.
+const stdinArg = "-"
+
+// batchSize is the number of inputs processed before their results are
+// printed.
+const batchSize = 50
+
+// batchResult is the outcome of processing one input.
+type batchResult struct {
+	Line   int    `json:"line"`
+	Input  string `json:"input"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// runBatch reads IDs or URLs, or JSON records holding them in the key field,
+// from the standard input, and processes them with runBatchFrom.
+func runBatch(key string, parse func(string) (string, error), f func(ctx context.Context, in ogle.Input, id string) (string, error)) {
+	runBatchFrom(os.Stdin, key, parse, f)
+}
+
+// runBatchFrom reads IDs or URLs, or JSON records holding them in the key
+// field, from r. Each ID is parsed and processed with f, which
+// returns the outcome, and one result is printed per input. It exits with an
+// error status if any input failed.
+func runBatchFrom(r io.Reader, key string, parse func(string) (string, error), f func(ctx context.Context, in ogle.Input, id string) (string, error)) {
+	if !noHeaders {
+		w.Header("LINE", "INPUT", "ID", "STATUS", "ERROR")
+	}
+	total, failed := 0, 0
+	err := ogle.ReadInputs(r, key, batchSize, func(batch []ogle.Input) error {
+		results, _ := ogle.Parallel(ctx, parallelism, batch, func(ctx context.Context, in ogle.Input) (batchResult, error) {
+			r := batchResult{Line: in.Line, Input: in.Value, Status: "error"}
+			err := in.Err
+			if err == nil {
+				if r.ID, err = parse(in.Value); err == nil {
+					r.Status, err = f(ctx, in, r.ID)
+				}
+			}
+			if err != nil {
+				r.Status, r.Error = "error", ogle.Explain(err).Message
+			}
+			return r, err
+		})
+		for _, r := range results {
+			total++
+			if r.Error != "" {
+				failed++
+			}
+			w.Row(r, r.Line, r.Input, r.ID, r.Status, r.Error)
+		}
+		return w.Flush()
+	})
+	if err != nil {
+		fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		fatal(err)
+	}
+	if failed > 0 {
+		fatal(fmt.Errorf("%d of %d inputs failed", failed, total))
+	}
+}
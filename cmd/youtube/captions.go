@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ronoaldo/ogle"
+	"golang.org/x/net/context"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Caption command line options
+var (
+	captionID       string
+	captionLanguage string
+	captionName     string
+	captionDraft    bool
+	captionFormat   string
+	captionOffset   time.Duration
+	captionOutput   string
+	captionFrom     string
+)
+
+func captionTrackFlags(fs *flag.FlagSet) {
+	fs.StringVar(&video, "video", "", "The `video` of the caption track: an ID or video URL.")
+	fs.StringVar(&captionID, "caption", "", "The caption track `id`. Alternatively, select it with -video and -language.")
+	fs.StringVar(&captionLanguage, "language", "", "The `language` of the caption track, like 'en' or 'pt-BR'.")
+	fs.StringVar(&captionName, "name", "", "The `name` of the caption track, to tell apart tracks in the same language.")
+}
+
+func captionFormatFlags(fs *flag.FlagSet) {
+	fs.StringVar(&captionFormat, "caption-format", "", "The caption `format`: "+strings.Join(ogle.SubtitleFormats, ", ")+". Defaults to the file extension.")
+	fs.DurationVar(&captionOffset, "offset", 0, "Shift all captions by `duration`, like 1.5s or -250ms.")
+}
+
+func captionDownloadFlags(fs *flag.FlagSet) {
+	captionTrackFlags(fs)
+	captionFormatFlags(fs)
+	fs.StringVar(&captionOutput, "out", "", "The `file` to save the captions to. Defaults to the standard output.")
+}
+
+func captionUploadFlags(fs *flag.FlagSet) {
+	captionTrackFlags(fs)
+	captionFormatFlags(fs)
+	fs.BoolVar(&captionDraft, "draft", false, "Upload the track as a draft, not visible to viewers.")
+}
+
+func captionConvertFlags(fs *flag.FlagSet) {
+	fs.StringVar(&captionFrom, "from", "", "The `format` of the input, required when reading from the standard input.")
+	fs.StringVar(&captionFormat, "to", "", "The `format` of the output. Defaults to the output file extension, or to the input format when writing to the standard output.")
+	fs.DurationVar(&captionOffset, "offset", 0, "Shift all captions by `duration`, like 1.5s or -250ms.")
+}
+
+func listCaptions(yt *youtube.Service) {
+	if video == "" || video == stdinArg {
+		fatal(ogle.UsageError("No video_id provided. Use the -video flag to define the video."))
+	}
+	cols := selectColumns(captionColumns, 0)
+	defer closeOutput()
+	tracks, err := videoCaptions(ctx, yt, video)
+	if err != nil {
+		fatal(err)
+	}
+	for i, c := range tracks {
+		w.Row(c, cols.Values(i+1, c)...)
+	}
+}
+
+// videoCaptions returns the caption tracks of the video.
+func videoCaptions(ctx context.Context, yt *youtube.Service, videoID string) ([]*youtube.Caption, error) {
+	resp, err := yt.Captions.List([]string{"id,snippet"}, videoID).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// findCaption returns the track of the video selected with -language and
+// -name, or nil if there is none.
+func findCaption(ctx context.Context, yt *youtube.Service, videoID string) (*youtube.Caption, error) {
+	tracks, err := videoCaptions(ctx, yt, videoID)
+	if err != nil {
+		return nil, err
+	}
+	var found *youtube.Caption
+	for _, c := range tracks {
+		if !strings.EqualFold(c.Snippet.Language, captionLanguage) || c.Snippet.Name != captionName {
+			continue
+		}
+		if found != nil {
+			return nil, ogle.UsageError("more than one %s caption track named %q, use -caption %s or -caption %s",
+				captionLanguage, captionName, found.Id, c.Id)
+		}
+		found = c
+	}
+	return found, nil
+}
+
+// selectedCaption returns the ID of the track given with -caption, or the one
+// found with -video, -language and -name.
+func selectedCaption(ctx context.Context, yt *youtube.Service) (string, error) {
+	if captionID != "" {
+		return captionID, nil
+	}
+	if video == "" || captionLanguage == "" {
+		return "", ogle.UsageError("Use -caption, or -video and -language, to select the caption track.")
+	}
+	c, err := findCaption(ctx, yt, video)
+	if err != nil {
+		return "", err
+	}
+	if c == nil {
+		return "", ogle.NewFailure("captionNotFound", fmt.Errorf("no %s caption track named %q in video %s", captionLanguage, captionName, video))
+	}
+	return c.Id, nil
+}
+
+// captionFileFormat returns the caption format set with -caption-format or
+// implied by the file name, falling back to def.
+func captionFileFormat(file, def string) (string, error) {
+	if captionFormat != "" {
+		return captionFormat, validSubtitleFormat(captionFormat)
+	}
+	if file == "" || file == stdinArg {
+		return def, nil
+	}
+	return ogle.SubtitleFormat(file)
+}
+
+func validSubtitleFormat(format string) error {
+	for _, f := range ogle.SubtitleFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return ogle.UsageError("invalid caption format %q, use one of: %s", format, strings.Join(ogle.SubtitleFormats, ", "))
+}
+
+// shiftSubtitles applies -offset to the captions read from r, writing them
+// in the same format.
+func shiftSubtitles(r io.Reader, format string) ([]byte, error) {
+	cues, err := ogle.ParseSubtitles(r, format)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = ogle.WriteSubtitles(&buf, ogle.ShiftCues(cues, captionOffset), format)
+	return buf.Bytes(), err
+}
+
+func downloadCaption(yt *youtube.Service) {
+	format, err := captionFileFormat(captionOutput, "srt")
+	if err != nil {
+		fatal(err)
+	}
+	id, err := selectedCaption(ctx, yt)
+	if err != nil {
+		fatal(err)
+	}
+	resp, err := yt.Captions.Download(id).Tfmt(format).Context(ctx).Download()
+	if err != nil {
+		fatal(fmt.Errorf("unable to download caption %s: %w", id, err))
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fatal(err)
+	}
+	if captionOffset != 0 {
+		if data, err = shiftSubtitles(bytes.NewReader(data), format); err != nil {
+			fatal(err)
+		}
+	}
+	if err := writeOutput(captionOutput, data); err != nil {
+		fatal(err)
+	}
+}
+
+// writeOutput saves data to file, or prints it if file is empty.
+func writeOutput(file string, data []byte) error {
+	if file == "" || file == stdinArg {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+func uploadCaption(yt *youtube.Service) {
+	if len(commandArgs) != 1 {
+		fatal(ogle.UsageError("expected one caption file to upload"))
+	}
+	file := commandArgs[0]
+	if video == "" || video == stdinArg || captionLanguage == "" {
+		fatal(ogle.UsageError("Use -video and -language to define the caption track to upload."))
+	}
+	format, err := captionFileFormat(file, "")
+	if err != nil {
+		fatal(err)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		fatal(err)
+	}
+	defer f.Close()
+	// Captions are parsed even when not shifted, to report errors before
+	// the upload.
+	data, err := shiftSubtitles(f, format)
+	if err != nil {
+		fatal(err)
+	}
+
+	existing := &youtube.Caption{Id: captionID}
+	if captionID == "" {
+		if existing, err = findCaption(ctx, yt, video); err != nil {
+			fatal(err)
+		}
+	}
+	caption := &youtube.Caption{
+		Snippet: &youtube.CaptionSnippet{
+			VideoId:  video,
+			Language: captionLanguage,
+			Name:     captionName,
+			IsDraft:  captionDraft,
+		},
+	}
+	status := "inserted"
+	if existing != nil {
+		status = "replaced"
+		caption.Id = existing.Id
+		caption.Snippet.ForceSendFields = []string{"IsDraft"}
+	}
+	if dryRun {
+		log.Printf("Dry run: %s caption track %q of video %s would be %s from %s",
+			captionLanguage, captionName, video, status, file)
+		return
+	}
+
+	var uploaded *youtube.Caption
+	if existing != nil {
+		uploaded, err = yt.Captions.Update([]string{"snippet"}, caption).Media(bytes.NewReader(data)).Context(ctx).Do()
+	} else {
+		uploaded, err = yt.Captions.Insert([]string{"snippet"}, caption).Media(bytes.NewReader(data)).Context(ctx).Do()
+	}
+	if err != nil {
+		fatal(fmt.Errorf("unable to upload captions: %w", err))
+	}
+	defer closeOutput()
+	if !noHeaders {
+		w.Header("ID", "STATUS")
+	}
+	w.Row(uploaded, uploaded.Id, status)
+}
+
+func deleteCaption(yt *youtube.Service) {
+	id, err := selectedCaption(ctx, yt)
+	if err != nil {
+		fatal(err)
+	}
+	if dryRun {
+		log.Printf("Dry run: caption track %s was not deleted", id)
+		return
+	}
+	confirm(fmt.Sprintf("Delete caption track %s?", id))
+	if err := yt.Captions.Delete(id).Context(ctx).Do(); err != nil {
+		fatal(fmt.Errorf("unable to delete caption %s: %w", id, err))
+	}
+	log.Printf("Caption track %s deleted", id)
+}
+
+func convertCaptions(*youtube.Service) {
+	if len(commandArgs) < 1 || len(commandArgs) > 2 {
+		fatal(ogle.UsageError("expected an input and an optional output file"))
+	}
+	in, out := commandArgs[0], ""
+	if len(commandArgs) == 2 {
+		out = commandArgs[1]
+	}
+
+	from := captionFrom
+	var err error
+	if from == "" {
+		if in == stdinArg {
+			fatal(ogle.UsageError("-from is required when reading from the standard input"))
+		}
+		if from, err = ogle.SubtitleFormat(in); err != nil {
+			fatal(err)
+		}
+	} else if err := validSubtitleFormat(from); err != nil {
+		fatal(err)
+	}
+	to, err := captionFileFormat(out, from)
+	if err != nil {
+		fatal(err)
+	}
+
+	var r io.Reader = os.Stdin
+	if in != stdinArg {
+		f, err := os.Open(in)
+		if err != nil {
+			fatal(err)
+		}
+		defer f.Close()
+		r = f
+	}
+	cues, err := ogle.ParseSubtitles(r, from)
+	if err != nil {
+		fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ogle.WriteSubtitles(&buf, ogle.ShiftCues(cues, captionOffset), to); err != nil {
+		fatal(err)
+	}
+	if err := writeOutput(out, buf.Bytes()); err != nil {
+		fatal(err)
+	}
+	if out != "" && out != stdinArg {
+		log.Printf("Converted %d captions from %s to %s", len(cues), from, to)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ronoaldo/ogle"
+	"golang.org/x/net/context"
+	"google.golang.org/api/youtube/v3"
+)
+
+func regionFlag(fs *flag.FlagSet) {
+	fs.StringVar(&region, "region", "", "The `region` code used to look up video categories, like 'BR'. Defaults to "+ogle.DefaultRegion+".")
+}
+
+// categoryRegion returns the region set with -region or in the
+// configuration, in upper case.
+func categoryRegion() string {
+	if region == "" {
+		return ogle.DefaultRegion
+	}
+	return strings.ToUpper(region)
+}
+
+// Category state
+var (
+	categoriesMu sync.Mutex
+	categories   *ogle.CategoryTable
+)
+
+func listCategories(yt *youtube.Service) {
+	cols := selectColumns(categoryColumns, 0)
+	defer closeOutput()
+	t, err := ogle.FetchCategories(ctx, yt, categoryRegion())
+	if err != nil {
+		fatal(err)
+	}
+	saveCategories(t)
+	for i, c := range t.Categories {
+		w.Row(c, cols.Values(i+1, c)...)
+	}
+}
+
+// saveCategories caches the table for name lookups and shell completion.
+func saveCategories(t *ogle.CategoryTable) {
+	categoriesMu.Lock()
+	categories = t
+	categoriesMu.Unlock()
+	if err := ogle.SaveCategories(ogle.CategoryFile("youtube", t.Region), t); err != nil {
+		log.Printf("Unable to update category cache: %v", err)
+	}
+	items := make([]ogle.CompletionItem, 0, len(t.Categories))
+	for _, c := range t.Categories {
+		if c.Assignable {
+			items = append(items, ogle.CompletionItem{Value: c.ID, Description: c.Title})
+		}
+	}
+	rememberCompletions("categories", items)
+}
+
+// categoryID resolves a category ID or name, like "Gaming", to its ID. Names
+// are looked up in the locally cached table of the region, which is fetched
+// when missing or expired. Numeric IDs are returned as is.
+func categoryID(ctx context.Context, yt *youtube.Service, s string) (string, error) {
+	if _, err := strconv.Atoi(s); err == nil || s == "" {
+		return s, nil
+	}
+	categoriesMu.Lock()
+	t := categories
+	categoriesMu.Unlock()
+	if t == nil {
+		var err error
+		if t, err = ogle.LoadCategories(ogle.CategoryFile("youtube", categoryRegion())); err != nil {
+			return "", err
+		}
+		if t == nil {
+			log.Printf("Fetching video categories of region %s", categoryRegion())
+			if t, err = ogle.FetchCategories(ctx, yt, categoryRegion()); err != nil {
+				return "", err
+			}
+			saveCategories(t)
+		}
+		categoriesMu.Lock()
+		categories = t
+		categoriesMu.Unlock()
+	}
+	c, err := t.Lookup(s)
+	if err != nil {
+		return "", err
+	}
+	return c.ID, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ronoaldo/ogle"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Columns available for each resource type, selectable with -columns.
+
+var channelColumns = ogle.Columns[*youtube.Channel]{
+	{Name: "num", Header: "#"},
+	{Name: "id", Header: "ID", Value: func(ch *youtube.Channel) interface{} { return ch.Id }, Fields: "id"},
+	{Name: "title", Header: "NAME", Value: func(ch *youtube.Channel) interface{} { return part(ch.Snippet).Title }, Fields: "snippet/title"},
+	{Name: "language", Header: "LANGUAGE", Value: func(ch *youtube.Channel) interface{} { return part(ch.Snippet).DefaultLanguage }, Fields: "snippet/defaultLanguage"},
+	{Name: "url", Header: "URL", Value: func(ch *youtube.Channel) interface{} { return part(ch.Snippet).CustomUrl }, Fields: "snippet/customUrl"},
+	{Name: "subscribers", Header: "SUBSCRIBERS", Value: func(ch *youtube.Channel) interface{} { return human.Count(part(ch.Statistics).SubscriberCount) }, Fields: "statistics/subscriberCount"},
+	{Name: "videos", Header: "VIDEOS", Value: func(ch *youtube.Channel) interface{} { return human.Count(part(ch.Statistics).VideoCount) }, Fields: "statistics/videoCount"},
+	{Name: "uploads", Header: "UPLOADS_PLAYLIST", Value: func(ch *youtube.Channel) interface{} { return part(part(ch.ContentDetails).RelatedPlaylists).Uploads }, Fields: "contentDetails/relatedPlaylists/uploads"},
+	{Name: "views", Header: "VIEWS", Value: func(ch *youtube.Channel) interface{} { return human.Count(part(ch.Statistics).ViewCount) }, Fields: "statistics/viewCount"},
+	{Name: "published", Header: "PUBLISHED_AT", Value: func(ch *youtube.Channel) interface{} { return human.Time(part(ch.Snippet).PublishedAt) }, Fields: "snippet/publishedAt", Extra: true},
+	{Name: "country", Header: "COUNTRY", Value: func(ch *youtube.Channel) interface{} { return part(ch.Snippet).Country }, Fields: "snippet/country", Extra: true},
+}
+
+var subscriptionColumns = ogle.Columns[*youtube.Subscription]{
+	{Name: "num", Header: "#"},
+	{Name: "title", Header: "NAME", Value: func(sub *youtube.Subscription) interface{} { return part(sub.SubscriberSnippet).Title }, Fields: "subscriberSnippet/title"},
+	{Name: "description", Header: "DESCRIPTION", Value: func(sub *youtube.Subscription) interface{} {
+		desc := strings.Split(part(sub.SubscriberSnippet).Description, "\n")[0]
+		return ogle.Truncate(desc, 40)
+	}, Fields: "subscriberSnippet/description"},
+	{Name: "url", Header: "URL", Value: func(sub *youtube.Subscription) interface{} {
+		return "https://www.youtube.com/channel/" + part(sub.SubscriberSnippet).ChannelId
+	}, Fields: "subscriberSnippet/channelId"},
+	{Name: "channel", Header: "CHANNEL_ID", Value: func(sub *youtube.Subscription) interface{} { return part(sub.SubscriberSnippet).ChannelId }, Fields: "subscriberSnippet/channelId", Extra: true},
+}
+
+var playlistColumns = ogle.Columns[*youtube.Playlist]{
+	{Name: "num", Header: "#"},
+	{Name: "id", Header: "ID", Value: func(p *youtube.Playlist) interface{} { return p.Id }, Fields: "id"},
+	{Name: "title", Header: "NAME", Value: func(p *youtube.Playlist) interface{} { return part(p.Snippet).Title }, Fields: "snippet/title"},
+	{Name: "channel", Header: "CHANNEL", Value: func(p *youtube.Playlist) interface{} { return part(p.Snippet).ChannelTitle }, Fields: "snippet/channelTitle"},
+	{Name: "privacy", Header: "VISIBILITY", Value: func(p *youtube.Playlist) interface{} { return part(p.Status).PrivacyStatus }, Fields: "status/privacyStatus"},
+	{Name: "videos", Header: "VIDEOS", Value: func(p *youtube.Playlist) interface{} { return human.Count(uint64(part(p.ContentDetails).ItemCount)) }, Fields: "contentDetails/itemCount"},
+	{Name: "published", Header: "PUBLISHED_AT", Value: func(p *youtube.Playlist) interface{} { return human.Time(part(p.Snippet).PublishedAt) }, Fields: "snippet/publishedAt", Extra: true},
+	{Name: "url", Header: "URL", Value: func(p *youtube.Playlist) interface{} {
+		return "https://www.youtube.com/playlist?list=" + p.Id
+	}, Fields: "id", Extra: true},
+}
+
+var playlistItemColumns = ogle.Columns[*youtube.PlaylistItem]{
+	{Name: "num", Header: "#"},
+	{Name: "published", Header: "PUBLISHED_AT", Value: func(item *youtube.PlaylistItem) interface{} { return human.Time(part(item.Snippet).PublishedAt) }, Fields: "snippet/publishedAt"},
+	{Name: "title", Header: "VIDEO", Value: func(item *youtube.PlaylistItem) interface{} { return part(item.Snippet).Title }, Fields: "snippet/title"},
+	{Name: "status", Header: "STATUS", Value: func(item *youtube.PlaylistItem) interface{} { return part(item.Status).PrivacyStatus }, Fields: "status/privacyStatus"},
+	{Name: "url", Header: "URL", Value: func(item *youtube.PlaylistItem) interface{} {
+		return "https://youtu.be/" + part(item.ContentDetails).VideoId
+	}, Fields: "contentDetails/videoId"},
+	{Name: "id", Header: "VIDEO_ID", Value: func(item *youtube.PlaylistItem) interface{} { return part(item.ContentDetails).VideoId }, Fields: "contentDetails/videoId", Extra: true},
+	{Name: "item", Header: "ITEM_ID", Value: func(item *youtube.PlaylistItem) interface{} { return item.Id }, Fields: "id", Extra: true},
+	{Name: "position", Header: "POSITION", Value: func(item *youtube.PlaylistItem) interface{} { return part(item.Snippet).Position }, Fields: "snippet/position", Extra: true},
+	{Name: "duration", Header: "DURATION", Value: func(item *youtube.PlaylistItem) interface{} {
+		d, ok := videoDurations[part(item.ContentDetails).VideoId]
+		if !ok {
+			return nil
+		}
+		return human.Duration(d)
+	}, Fields: "contentDetails/videoId", Extra: true},
+}
+
+// videoDurations maps video IDs to their ISO 8601 durations, fetched with
+// fetchDurations for the duration column of playlist items.
+var videoDurations = make(map[string]string)
+
+var liveBroadcastColumns = ogle.Columns[*youtube.LiveBroadcast]{
+	{Name: "num", Header: "#"},
+	{Name: "published", Header: "PUBLISHED_AT", Value: func(item *youtube.LiveBroadcast) interface{} { return human.Time(part(item.Snippet).PublishedAt) }, Fields: "snippet/publishedAt"},
+	{Name: "id", Header: "ID", Value: func(item *youtube.LiveBroadcast) interface{} { return item.Id }, Fields: "id"},
+	{Name: "title", Header: "TITLE", Value: func(item *youtube.LiveBroadcast) interface{} { return part(item.Snippet).Title }, Fields: "snippet/title"},
+	{Name: "status", Header: "STATUS", Value: func(item *youtube.LiveBroadcast) interface{} { return part(item.Status).LifeCycleStatus }, Fields: "status/lifeCycleStatus"},
+	{Name: "url", Header: "URL", Value: func(item *youtube.LiveBroadcast) interface{} {
+		return "https://studio.youtube.com/video/" + item.Id + "/livestreaming"
+	}, Fields: "id"},
+	{Name: "scheduled", Header: "SCHEDULED_START", Value: func(item *youtube.LiveBroadcast) interface{} {
+		return human.Time(part(item.Snippet).ScheduledStartTime)
+	}, Fields: "snippet/scheduledStartTime", Extra: true},
+	{Name: "privacy", Header: "VISIBILITY", Value: func(item *youtube.LiveBroadcast) interface{} { return part(item.Status).PrivacyStatus }, Fields: "status/privacyStatus", Extra: true},
+	{Name: "duration", Header: "DURATION", Value: func(item *youtube.LiveBroadcast) interface{} {
+		start, err := time.Parse(time.RFC3339, part(item.Snippet).ActualStartTime)
+		if err != nil {
+			return nil
+		}
+		end, err := time.Parse(time.RFC3339, part(item.Snippet).ActualEndTime)
+		if err != nil {
+			return nil
+		}
+		return human.Elapsed(end.Sub(start))
+	}, Fields: "snippet/actualStartTime,snippet/actualEndTime", Extra: true},
+}
+
+var captionColumns = ogle.Columns[*youtube.Caption]{
+	{Name: "num", Header: "#"},
+	{Name: "id", Header: "ID", Value: func(item *youtube.Caption) interface{} { return item.Id }, Fields: "id"},
+	{Name: "language", Header: "LANGUAGE", Value: func(item *youtube.Caption) interface{} { return part(item.Snippet).Language }, Fields: "snippet/language"},
+	{Name: "name", Header: "NAME", Value: func(item *youtube.Caption) interface{} { return part(item.Snippet).Name }, Fields: "snippet/name"},
+	{Name: "kind", Header: "KIND", Value: func(item *youtube.Caption) interface{} { return part(item.Snippet).TrackKind }, Fields: "snippet/trackKind"},
+	{Name: "draft", Header: "DRAFT", Value: func(item *youtube.Caption) interface{} { return part(item.Snippet).IsDraft }, Fields: "snippet/isDraft"},
+	{Name: "updated", Header: "UPDATED_AT", Value: func(item *youtube.Caption) interface{} { return human.Time(part(item.Snippet).LastUpdated) }, Fields: "snippet/lastUpdated"},
+	{Name: "status", Header: "STATUS", Value: func(item *youtube.Caption) interface{} { return part(item.Snippet).Status }, Fields: "snippet/status", Extra: true},
+	{Name: "audio", Header: "AUDIO_TRACK", Value: func(item *youtube.Caption) interface{} { return part(item.Snippet).AudioTrackType }, Fields: "snippet/audioTrackType", Extra: true},
+}
+
+var categoryColumns = ogle.Columns[ogle.Category]{
+	{Name: "num", Header: "#"},
+	{Name: "id", Header: "ID", Value: func(c ogle.Category) interface{} { return c.ID }},
+	{Name: "title", Header: "TITLE", Value: func(c ogle.Category) interface{} { return c.Title }},
+	{Name: "assignable", Header: "ASSIGNABLE", Value: func(c ogle.Category) interface{} { return c.Assignable }},
+}
+
+// part returns the resource part p, or an empty one if it is nil, as parts
+// are omitted by the API when not requested with -fields or not available.
+func part[P any](p *P) *P {
+	if p == nil {
+		return new(P)
+	}
+	return p
+}
+
+// selectColumns returns the columns chosen with -columns, writing their
+// header unless -no-headers is set or the listing is being resumed.
+func selectColumns[T any](all ogle.Columns[T], count int) ogle.Columns[T] {
+	cols, err := all.Select(columnNames)
+	if err != nil {
+		fatal(ogle.UsageError("%v", err))
+	}
+	if count == 0 && !noHeaders {
+		w.Header(cols.Headers()...)
+	}
+	return cols
+}
+
+// hasColumn reports if the named column is selected and printed.
+func hasColumn[T any](cols ogle.Columns[T], name string) bool {
+	if outputTemplate != "" || !columnFormats[outputFormat] {
+		return false
+	}
+	for _, n := range cols.Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldsMask returns the partial response mask for a listing of the selected
+// columns, including any extra fields and those set with -fields. Outputs that
+// print the whole resource only use the -fields, if any. An empty mask means
+// that all fields must be requested.
+func fieldsMask[T any](cols ogle.Columns[T], extra ...string) googleapi.Field {
+	fields := make([]string, 0)
+	if outputTemplate == "" && columnFormats[outputFormat] {
+		colFields := cols.Fields()
+		if colFields == nil {
+			return ""
+		}
+		// At least one field is needed when only the row number is printed.
+		fields = append(append(fields, "id"), colFields...)
+		fields = append(fields, extra...)
+	}
+	if fieldNames != "" {
+		fields = append(fields, strings.Split(fieldNames, ",")...)
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return ogle.ListFields(fields...)
+}
+
+// columnFormats are the output formats that print only the selected columns.
+var columnFormats = map[string]bool{"table": true, "tsv": true, "csv": true}
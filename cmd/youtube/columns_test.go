@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ronoaldo/ogle"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// TestColumnsMissingParts checks that every column can be printed for
+// resources without parts, as returned when -fields leaves them out.
+func TestColumnsMissingParts(t *testing.T) {
+	values := map[string]func() []interface{}{
+		"channel":      func() []interface{} { return channelColumns.Values(1, &youtube.Channel{}) },
+		"subscription": func() []interface{} { return subscriptionColumns.Values(1, &youtube.Subscription{}) },
+		"playlist":     func() []interface{} { return playlistColumns.Values(1, &youtube.Playlist{}) },
+		"playlistItem": func() []interface{} { return playlistItemColumns.Values(1, &youtube.PlaylistItem{}) },
+		"broadcast":    func() []interface{} { return liveBroadcastColumns.Values(1, &youtube.LiveBroadcast{}) },
+		"caption":      func() []interface{} { return captionColumns.Values(1, &youtube.Caption{}) },
+		"channelWithoutRelatedPlaylists": func() []interface{} {
+			return channelColumns.Values(1, &youtube.Channel{ContentDetails: &youtube.ChannelContentDetails{}})
+		},
+	}
+	for name, f := range values {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("panic: %v", r)
+				}
+			}()
+			f()
+		})
+	}
+}
+
+func TestFieldsMask(t *testing.T) {
+	defer func(format, template, columns, fields string) {
+		outputFormat, outputTemplate, columnNames, fieldNames = format, template, columns, fields
+	}(outputFormat, outputTemplate, columnNames, fieldNames)
+
+	tests := []struct {
+		name     string
+		format   string
+		template string
+		columns  string
+		fields   string
+		extra    []string
+		want     googleapi.Field
+	}{
+		{
+			name:    "selected columns",
+			format:  "table",
+			columns: "title",
+			want:    "nextPageToken,items(id,snippet/title)",
+		},
+		{
+			name:    "row number only",
+			format:  "csv",
+			columns: "num",
+			want:    "nextPageToken,items(id)",
+		},
+		{
+			name:    "shared fields are requested once",
+			format:  "tsv",
+			columns: "id,title,url",
+			want:    "nextPageToken,items(id,snippet/title)",
+		},
+		{
+			name:    "default columns",
+			format:  "table",
+			columns: "",
+			want:    "nextPageToken,items(id,snippet/title,snippet/channelTitle,status/privacyStatus,contentDetails/itemCount)",
+		},
+		{
+			name:    "extra and -fields",
+			format:  "table",
+			columns: "title",
+			fields:  "status/privacyStatus, snippet/title",
+			extra:   []string{"snippet/description"},
+			want:    "nextPageToken,items(id,snippet/title,snippet/description,status/privacyStatus)",
+		},
+		{
+			name:   "whole resources",
+			format: "json",
+			want:   "",
+		},
+		{
+			name:   "whole resources with -fields",
+			format: "jsonl",
+			fields: "id,snippet/title",
+			extra:  []string{"snippet/description"},
+			want:   "nextPageToken,items(id,snippet/title)",
+		},
+		{
+			name:     "templates print whole resources",
+			format:   "table",
+			template: "{{.Id}}",
+			columns:  "title",
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFormat, outputTemplate, columnNames, fieldNames = tt.format, tt.template, tt.columns, tt.fields
+			cols, err := playlistColumns.Select(columnNames)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fieldsMask(cols, tt.extra...); got != tt.want {
+				t.Errorf("fieldsMask() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Columns without fields need the whole resource.
+	outputFormat, outputTemplate, fieldNames = "table", "", ""
+	noFields := ogle.Columns[*youtube.Playlist]{{Name: "x", Value: func(*youtube.Playlist) interface{} { return 1 }}}
+	if got := fieldsMask(noFields); got != "" {
+		t.Errorf("fieldsMask() without column fields = %q, want \"\"", got)
+	}
+}
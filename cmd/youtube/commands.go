@@ -0,0 +1,779 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ronoaldo/ogle"
+	"google.golang.org/api/youtube/v3"
+)
+
+// command is a youtube subcommand, like "playlists list".
+type command struct {
+	// Name is the command name, made of one or more words.
+	Name string
+
+	// Aliases are alternative names, including the ones accepted by the
+	// deprecated -cmd flag.
+	Aliases []string
+
+	// Short is a one line description shown in the command list.
+	Short string
+
+	// Args describes the required arguments, shown in the usage line.
+	Args string
+
+	// Long is an optional detailed description.
+	Long string
+
+	// Examples are sample invocations shown in the command help.
+	Examples []string
+
+	// Flags register the command line options accepted by the command.
+	Flags []func(fs *flag.FlagSet)
+
+	// Offline commands do not call the API, so no client is created.
+	Offline bool
+
+	// Positional commands accept arguments after the flags.
+	Positional bool
+
+	// Raw commands receive all their arguments unparsed, as commandArgs.
+	Raw bool
+
+	// Hidden commands are not listed in the help.
+	Hidden bool
+
+	// Complete suggests values for the positional arguments in the shell
+	// completion.
+	Complete func() []ogle.CompletionItem
+
+	// Run executes the command. The service is nil for Offline commands.
+	Run func(yt *youtube.Service)
+}
+
+// commands is the registry of all youtube subcommands, in the order they are
+// listed in the help.
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{
+			Name:     "channels list",
+			Aliases:  []string{"channels"},
+			Short:    "list your channels",
+			Examples: []string{"youtube channels list -columns id,title,subscribers"},
+			Flags:    []func(*flag.FlagSet){listFlags},
+			Run:      listChannels,
+		},
+		{
+			Name:     "subscribers list",
+			Aliases:  []string{"subscribers", "subs"},
+			Short:    "list the subscribers of your channel",
+			Examples: []string{"youtube subscribers list -o csv > subscribers.csv"},
+			Flags:    []func(*flag.FlagSet){listFlags},
+			Run:      listSubscribers,
+		},
+		{
+			Name:    "playlists list",
+			Aliases: []string{"playlists"},
+			Short:   "list playlists of your channel or of -channel",
+			Examples: []string{
+				"youtube playlists list",
+				"youtube playlists list -channel UC_x5XG1OV2P6uZZ5FSM9Ttw -o json",
+			},
+			Flags: []func(*flag.FlagSet){channelFlag, listFlags},
+			Run:   listPlaylists,
+		},
+		{
+			Name:    "playlists items",
+			Aliases: []string{"playlist-items", "playlist-videos"},
+			Short:   "list videos in a playlist",
+			Args:    "-playlist playlist_id",
+			Long: `The duration column, selected with -columns, looks up the videos of each page
+with one extra API call.`,
+			Examples: []string{
+				"youtube playlists items -playlist PLxxxx -columns id -no-headers",
+				"youtube playlists items -playlist PLxxxx -columns num,title,duration",
+			},
+			Flags: []func(*flag.FlagSet){playlistFlag, listFlags},
+			Run:   listPlaylistVideos,
+		},
+		{
+			Name:    "playlists dedup",
+			Aliases: []string{"playlist-dedup", "dedup-playlist"},
+			Short:   "remove duplicate videos from a playlist",
+			Args:    "-playlist playlist_id",
+			Examples: []string{
+				"youtube playlists dedup -playlist PLxxxx -parallel 8",
+				"youtube playlists list -columns id -no-headers | youtube playlists dedup -playlist - -yes",
+			},
+			Flags: []func(*flag.FlagSet){playlistFlag, parallelFlag, outputFlags},
+			Run:   removeDuplicatesFromPlaylist,
+		},
+		{
+			Name:    "videos update",
+			Aliases: []string{"video-update"},
+			Short:   "update details about a video",
+			Args:    "-video video_id",
+			Long: `Only the fields given are changed; the others keep their current values.
+Tags given as +tag or -tag are added to or removed from the current tags.
+With -edit, the title, description and tags are opened in $EDITOR and only
+the fields changed in the editor are updated.
+
+Titles, descriptions and tags are checked against YouTube's limits before
+any request, and every violation is reported with its field and offset.
+
+With -video -, IDs or URLs are read from the standard input, one per line,
+and one result is printed for each. Lines can also be JSON records with a
+"video" or "id" field, whose "title", "description", "category", "tags",
+"privacy", "publishAt", "license", "embeddable", "publicStatsViewable",
+"madeForKids", "defaultLanguage", "defaultAudioLanguage", "recordingDate"
+and "location" fields override the flags for that video.`,
+			Examples: []string{
+				`youtube videos update -video dQw4w9WgXcQ -title "New title" -tags "music,80s"`,
+				`youtube videos update -video dQw4w9WgXcQ -tags "+synthwave,-80s"`,
+				"youtube videos update -video dQw4w9WgXcQ -edit",
+				"youtube videos update -video dQw4w9WgXcQ -publish-at 2024-05-01T18:00:00-03:00 -embeddable=false",
+				"youtube videos update -video dQw4w9WgXcQ -language en -audio-language pt-BR -recorded 2024-04-20 -location -23.55,-46.63",
+				`youtube playlists items -playlist PLxxxx -columns id -no-headers | youtube videos update -video - -tags "music,80s"`,
+			},
+			Flags: []func(*flag.FlagSet){videoFlag, videoEditFlags, videoStatusFlags, editorFlag, regionFlag, parallelFlag, outputFlags},
+			Run:   videoUpdate,
+		},
+		{
+			Name:    "videos upload",
+			Aliases: []string{"upload"},
+			Short:   "upload a video file",
+			Args:    "file",
+			Long: `The upload is sent in chunks. If it is interrupted, run the same command with
+-resume to continue from the last chunk received. The new video ID and URL
+are printed once the upload completes.
+
+Metadata is also read from a JSON or YAML sidecar file next to the video, like
+video.mp4.yaml, where the video ID is recorded after the upload. Flags
+override the values in the sidecar. For example:
+
+  title: "Live coding #42"
+  description: |
+    Building a command line tool in Go.
+  tags: [go, cli]
+  category: 28
+  privacy: private
+  thumbnail: thumbnail.jpg
+  playlists: [PLxxxx]
+  schedule: 2024-05-01T18:00:00Z
+  defaultLanguage: en
+  localizations:
+    pt-BR:
+      title: "Programando ao vivo #42"`,
+			Examples: []string{
+				`youtube videos upload -title "Live coding #42" -privacy unlisted -playlist PLxxxx video.mp4`,
+				"youtube videos upload -resume video.mp4",
+			},
+			Flags:      []func(*flag.FlagSet){uploadFlags, regionFlag, outputFlags},
+			Positional: true,
+			Run:        uploadVideo,
+		},
+		{
+			Name:    "videos upload-watch",
+			Aliases: []string{"upload-watch"},
+			Short:   "upload the videos dropped in a directory",
+			Args:    "directory",
+			Long: `Video files are uploaded once they are left unchanged for -settle, using the
+metadata in their sidecar files, like video.mp4.yaml, which hold the title,
+description, tags, category, privacy, thumbnail, playlists, schedule and
+localizations of the video. The resulting video ID is recorded in the sidecar,
+and both files are moved to the done or failed subdirectory.`,
+			Examples: []string{
+				"youtube videos upload-watch -privacy unlisted /srv/renders",
+				"youtube videos upload-watch -once -settle 0 .",
+			},
+			Flags:      []func(*flag.FlagSet){uploadFlags, watchFlags, regionFlag, outputFlags},
+			Positional: true,
+			Run:        watchUploads,
+		},
+		{
+			Name:    "thumbnails set",
+			Aliases: []string{"thumbnail-set"},
+			Short:   "set the custom thumbnail of a video",
+			Args:    "-video video_id image",
+			Long: `The image must be a JPEG, PNG or GIF file of up to 2 MB, with a 16:9 aspect
+ratio and at least 640 pixels wide. With -fit, other images are cropped at the
+center, resized to 1280x720 and encoded as JPEG.`,
+			Examples: []string{
+				"youtube thumbnails set -video dQw4w9WgXcQ thumbnail.png",
+				"youtube thumbnails set -video dQw4w9WgXcQ -fit photo.jpg",
+			},
+			Flags:      []func(*flag.FlagSet){videoFlag, thumbnailSetFlags},
+			Positional: true,
+			Run:        setVideoThumbnail,
+		},
+		{
+			Name:    "thumbnails get",
+			Aliases: []string{"thumbnail-get"},
+			Short:   "download the thumbnails of videos",
+			Args:    "-video video_id | video_id...",
+			Long: `Files are named after the video ID and size, like dQw4w9WgXcQ-maxres.jpg. By
+default only the largest thumbnail available is saved.`,
+			Examples: []string{
+				"youtube thumbnails get -video dQw4w9WgXcQ -size maxres,high,default",
+				"youtube playlists items -playlist PLxxxx -columns id -no-headers | youtube thumbnails get -video - -dir thumbs",
+			},
+			Flags:      []func(*flag.FlagSet){videoFlag, thumbnailGetFlags, parallelFlag, outputFlags},
+			Positional: true,
+			Run:        getThumbnails,
+		},
+		{
+			Name:    "thumbnails render",
+			Aliases: []string{"thumbnail-render"},
+			Short:   "render a thumbnail from a template",
+			Args:    "-template file",
+			Long: `The template draws text and image layers over a background image or color,
+producing a 1280x720 thumbnail. Text layers are Go templates whose variables
+are set with -var. Colors are quoted in YAML, as # starts a comment. For
+example:
+
+  background: base.png
+  layers:
+    - image: logo.png
+      x: 40
+      y: 40
+      width: 200
+      opacity: 0.8
+    - text: "#{{.episode}}"
+      font: fonts/Anton-Regular.ttf
+      size: 160
+      color: "#ffcc00"
+      outline: 8
+      x: 1240
+      y: 40
+      align: right
+    - text: "{{.title}}"
+      size: 90
+      outline: 6
+      outlineColor: "#000000c0"
+      maxWidth: 1200
+      x: 40
+      y: 440`,
+			Examples: []string{
+				`youtube thumbnails render -template series.yaml -var episode=42 -var title="Live coding" -out ep42.jpg`,
+				`youtube thumbnails render -template series.yaml -var episode=42 -var title="Live coding" -video dQw4w9WgXcQ`,
+			},
+			Flags:   []func(*flag.FlagSet){thumbnailRenderFlags},
+			Offline: true,
+			Run:     renderThumbnail,
+		},
+		{
+			Name:     "captions list",
+			Aliases:  []string{"captions"},
+			Short:    "list the caption tracks of a video",
+			Args:     "-video video_id",
+			Examples: []string{"youtube captions list -video dQw4w9WgXcQ"},
+			Flags:    []func(*flag.FlagSet){videoFlag, columnFlags},
+			Run:      listCaptions,
+		},
+		{
+			Name:    "captions download",
+			Aliases: []string{"caption-download"},
+			Short:   "download a caption track as SRT, WebVTT or SBV",
+			Args:    "-caption id | -video video_id -language lang",
+			Examples: []string{
+				"youtube captions download -video dQw4w9WgXcQ -language en -out en.srt",
+				"youtube captions download -caption AUieDaZ... -caption-format vtt -offset -1.5s",
+			},
+			Flags: []func(*flag.FlagSet){captionDownloadFlags},
+			Run:   downloadCaption,
+		},
+		{
+			Name:    "captions upload",
+			Aliases: []string{"caption-upload"},
+			Short:   "upload or replace a caption track",
+			Args:    "-video video_id -language lang file",
+			Long: `The track with the same -language and -name is replaced, if it exists. The file
+is checked before the upload and, with -offset, its captions are shifted.`,
+			Examples: []string{
+				"youtube captions upload -video dQw4w9WgXcQ -language pt-BR -name Português pt.srt",
+				"youtube captions upload -video dQw4w9WgXcQ -language en -offset 2s -draft en.vtt",
+			},
+			Flags:      []func(*flag.FlagSet){captionUploadFlags, outputFlags},
+			Positional: true,
+			Run:        uploadCaption,
+		},
+		{
+			Name:    "captions delete",
+			Aliases: []string{"caption-delete"},
+			Short:   "delete a caption track",
+			Args:    "-caption id | -video video_id -language lang",
+			Examples: []string{
+				"youtube captions delete -video dQw4w9WgXcQ -language en -name Draft",
+			},
+			Flags: []func(*flag.FlagSet){captionTrackFlags},
+			Run:   deleteCaption,
+		},
+		{
+			Name:    "captions convert",
+			Aliases: []string{"caption-convert"},
+			Short:   "convert caption files between SRT, WebVTT and SBV",
+			Args:    "input [output]",
+			Long: `Formats are taken from the file extensions, unless set with -from and -to. Use
+- as input to read from the standard input, which requires -from. Without an
+output, captions are printed to the standard output in the input format, or
+in the one set with -to.`,
+			Examples: []string{
+				"youtube captions convert subtitles.sbv subtitles.srt",
+				"youtube captions convert -offset -2.5s -to vtt en.srt",
+			},
+			Flags:      []func(*flag.FlagSet){captionConvertFlags},
+			Offline:    true,
+			Positional: true,
+			Run:        convertCaptions,
+		},
+		{
+			Name:    "categories list",
+			Aliases: []string{"categories"},
+			Short:   "list the video categories of a region",
+			Long: `The region is set with -region or the region setting, and defaults to US.
+The list is cached, so -category accepts category names, like "Gaming" or
+"film & animation", in any case. Only assignable categories can be used.`,
+			Examples: []string{
+				"youtube categories list -region BR",
+				"youtube config set region BR",
+			},
+			Flags: []func(*flag.FlagSet){regionFlag, columnFlags},
+			Run:   listCategories,
+		},
+		{
+			Name:     "lives list",
+			Aliases:  []string{"lives"},
+			Short:    "list upcoming and past broadcasts",
+			Examples: []string{"youtube lives list -columns id,title,scheduled"},
+			Flags:    []func(*flag.FlagSet){pageFlags},
+			Run:      listLives,
+		},
+		{
+			Name:    "lives update",
+			Aliases: []string{"live-update"},
+			Short:   "update title and description of a broadcast",
+			Args:    "-video video_id",
+			Long: `With -video -, IDs or URLs are read from the standard input, one per line,
+and one result is printed for each. Lines can also be JSON records with a
+"video" or "id" field, whose "title" and "description" fields override the
+flags for that broadcast.`,
+			Examples: []string{`youtube lives update -video abc123 -title "Live coding #42"`},
+			Flags:    []func(*flag.FlagSet){videoFlag, liveEditFlags, parallelFlag, outputFlags},
+			Run:      updateLive,
+		},
+		{
+			Name:  "config list",
+			Short: "list the settings and where their values come from",
+			Long: `Settings are read from the configuration file, where they can be grouped in
+profiles selected with -profile. Options given on the command line override
+the file, and environment variables, like YOUTUBE_CHANNEL, override both.`,
+			Examples: []string{"youtube config list -profile work"},
+			Flags:    []func(*flag.FlagSet){outputFlags},
+			Offline:  true,
+			Run:      func(*youtube.Service) { configList() },
+		},
+		{
+			Name:       "config get",
+			Short:      "print the value of a setting",
+			Args:       "key",
+			Examples:   []string{"youtube config get channel"},
+			Offline:    true,
+			Positional: true,
+			Complete:   settingCompletions,
+			Run:        func(*youtube.Service) { configGet(commandArgs) },
+		},
+		{
+			Name:  "config set",
+			Short: "change a setting in the configuration file",
+			Args:  "key value",
+			Long: `The setting is saved in the profile selected with -profile, or as a default
+for all profiles. Use an empty value to remove it.`,
+			Examples: []string{
+				"youtube config set output json",
+				"youtube config set -profile work account work@example.com",
+				`youtube config set timezone ""`,
+			},
+			Offline:    true,
+			Positional: true,
+			Complete:   settingCompletions,
+			Run:        func(*youtube.Service) { configSet(commandArgs) },
+		},
+		{
+			Name:    "cache clear",
+			Aliases: []string{"cache-clear"},
+			Short:   "remove cached API responses",
+			Offline: true,
+			Run:     func(*youtube.Service) { clearCache() },
+		},
+		{
+			Name:    "logout",
+			Aliases: []string{"reauth"},
+			Short:   "revoke credentials",
+			Offline: true,
+			Run:     func(*youtube.Service) { logout() },
+		},
+		{
+			Name:       "help",
+			Aliases:    []string{"list"},
+			Short:      "show help about all commands or a single one",
+			Args:       "[command]",
+			Examples:   []string{"youtube help playlists items"},
+			Offline:    true,
+			Positional: true,
+			Run:        func(*youtube.Service) { help(commandArgs) },
+		},
+		{
+			Name:  "completion",
+			Short: "print the shell completion script",
+			Args:  "bash|zsh|fish",
+			Long: `The script completes commands, flags and the values of -playlist, -video
+and -channel. Values are read from a local cache of your playlists, uploads
+and broadcasts, filled by "youtube completion refresh" and updated when
+playlists and broadcasts are listed.`,
+			Examples: []string{
+				"source <(youtube completion bash)",
+				"youtube completion fish > ~/.config/fish/completions/youtube.fish",
+			},
+			Offline:    true,
+			Positional: true,
+			Complete:   shellCompletions,
+			Run:        func(*youtube.Service) { completionScript(commandArgs) },
+		},
+		{
+			Name:     "completion refresh",
+			Short:    "cache your playlists, uploads and broadcasts for completion",
+			Examples: []string{"youtube completion refresh -max-results 500"},
+			Flags:    []func(*flag.FlagSet){maxResultsFlag},
+			Run:      refreshCompletions,
+		},
+		{
+			Name:    ogle.CompleteCommand,
+			Short:   "print completion candidates for the shell scripts",
+			Offline: true,
+			Raw:     true,
+			Hidden:  true,
+			Run:     func(*youtube.Service) { complete(commandArgs) },
+		},
+	}
+}
+
+// commandArgs are the positional arguments left after the command name and
+// flags are parsed.
+var commandArgs []string
+
+// lookupCommand finds the command named by the first words of args, returning
+// it and the remaining arguments.
+func lookupCommand(args []string) (*command, []string) {
+	var (
+		found *command
+		rest  = args
+		words = 0
+	)
+	for _, c := range commands {
+		names := append([]string{c.Name}, c.Aliases...)
+		for _, name := range names {
+			parts := strings.Fields(name)
+			if len(parts) <= words || len(parts) > len(args) {
+				continue
+			}
+			if strings.Join(args[:len(parts)], " ") == name {
+				found, rest, words = c, args[len(parts):], len(parts)
+			}
+		}
+	}
+	return found, rest
+}
+
+// flagSet returns a new flag set with the global and command specific flags.
+func (c *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("youtube "+c.Name, flag.ContinueOnError)
+	globalFlags(fs)
+	for _, f := range c.Flags {
+		f(fs)
+	}
+	fs.Usage = func() { c.usage(fs.Output(), fs) }
+	return fs
+}
+
+// usage prints the generated help for the command, including the flags
+// registered in fs.
+func (c *command) usage(out io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintf(out, "Usage: youtube %s", c.Name)
+	if c.Args != "" {
+		fmt.Fprintf(out, " %s", c.Args)
+	}
+	fmt.Fprintf(out, " [flags]\n\n%s.\n", capitalize(c.Short))
+	if c.Long != "" {
+		fmt.Fprintf(out, "\n%s\n", c.Long)
+	}
+	if len(c.Aliases) > 0 {
+		fmt.Fprintf(out, "\nAliases: %s\n", strings.Join(c.Aliases, ", "))
+	}
+	if len(c.Examples) > 0 {
+		fmt.Fprintf(out, "\nExamples:\n")
+		for _, e := range c.Examples {
+			fmt.Fprintf(out, "  %s\n", e)
+		}
+	}
+	fmt.Fprintf(out, "\nFlags:\n")
+	fs.SetOutput(out)
+	fs.PrintDefaults()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// help prints the list of commands, or the help of the named command.
+func help(args []string) {
+	if len(args) > 0 {
+		c, rest := lookupCommand(args)
+		if c == nil || len(rest) > 0 {
+			fatal(ogle.UsageError("unknown command %q", strings.Join(args, " ")))
+		}
+		c.usage(os.Stdout, c.flagSet())
+		return
+	}
+	usage(os.Stdout)
+}
+
+// usage prints the list of all commands.
+func usage(out io.Writer) {
+	fmt.Fprintf(out, "Usage: youtube <command> [flags]\n\nCommands:\n")
+	tw := ogle.NewTabWriter(out)
+	for _, c := range commands {
+		if !c.Hidden {
+			tw.Println("  "+c.Name+" ", c.Short)
+		}
+	}
+	tw.Flush()
+	fmt.Fprintf(out, "\nUse \"youtube help <command>\" for more information about a command.\n")
+}
+
+// parseCommandLine finds the command to run and parses its flags. The flags
+// shared by all commands may appear before or after the command name. The
+// deprecated -cmd flag is still accepted.
+func parseCommandLine(args []string) *command {
+	if name, rest, ok := deprecatedCommand(args); ok {
+		c, _ := lookupCommand([]string{name})
+		if c == nil {
+			log.Printf("Unknown command: '%s'", name)
+			usage(os.Stderr)
+			os.Exit(ogle.ExitUsage)
+		}
+		log.Printf("Warning: -cmd is deprecated, use 'youtube %s' instead.", c.Name)
+		parseCommandFlags(c, nil, rest)
+		return c
+	}
+
+	top := flag.NewFlagSet("youtube", flag.ContinueOnError)
+	top.Usage = func() { usage(top.Output()) }
+	globalFlags(top)
+	if err := parseFlags(top, args); err != nil {
+		fatal(err)
+	}
+	if top.NArg() == 0 {
+		c, _ := lookupCommand([]string{"help"})
+		return c
+	}
+	c, rest := lookupCommand(top.Args())
+	if c == nil {
+		log.Printf("Unknown command: '%s'", strings.Join(top.Args(), " "))
+		usage(os.Stderr)
+		os.Exit(ogle.ExitUsage)
+	}
+	parseCommandFlags(c, top, rest)
+	return c
+}
+
+// parseCommandFlags parses the command flags from args. Registering the flags
+// again resets them to their defaults, so the values already parsed by top
+// are applied again.
+func parseCommandFlags(c *command, top *flag.FlagSet, args []string) {
+	if c.Raw {
+		commandArgs = args
+		return
+	}
+	fs := c.flagSet()
+	if top != nil {
+		top.Visit(func(f *flag.Flag) {
+			fs.Set(f.Name, f.Value.String())
+		})
+	}
+	if err := parseFlags(fs, args); err != nil {
+		fatal(err)
+	}
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+	commandArgs = fs.Args()
+}
+
+// deprecatedCommand extracts the value of the -cmd flag from args, returning
+// the remaining arguments.
+func deprecatedCommand(args []string) (name string, rest []string, ok bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		flagName := strings.TrimLeft(arg, "-")
+		if !strings.HasPrefix(arg, "-") || !(flagName == "cmd" || strings.HasPrefix(flagName, "cmd=")) {
+			continue
+		}
+		rest = append(rest, args[:i]...)
+		if strings.HasPrefix(flagName, "cmd=") {
+			name = strings.TrimPrefix(flagName, "cmd=")
+		} else if i+1 < len(args) {
+			name = args[i+1]
+			i++
+		}
+		return name, append(rest, args[i+1:]...), true
+	}
+	return "", args, false
+}
+
+// parseFlags parses the arguments, exiting successfully if help was
+// requested.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == flag.ErrHelp {
+		os.Exit(ogle.ExitOK)
+	}
+	if err != nil {
+		return ogle.UsageError("%v", err)
+	}
+	return nil
+}
+
+// globalFlags registers the options shared by all commands.
+func globalFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&useCache, "cache", false, "Cache API responses on disk and revalidate them using ETags.")
+	fs.DurationVar(&cacheTTL, "cache-ttl", 0, "How long cached responses are used without revalidation.")
+	fs.Int64Var(&cacheMaxSize, "cache-max-size", 50, "Maximum size of the response cache, in `megabytes`.")
+	fs.StringVar(&errorFormat, "errors", "text", "The `format` of error messages: text or json.")
+	fs.Float64Var(&rateLimit, "rate", 0, "Maximum API `requests` per second. Use 0 for no limit.")
+	fs.IntVar(&rateBurst, "burst", 1, "Maximum `requests` allowed at once when -rate is set.")
+	fs.BoolVar(&dryRun, "dry-run", false, "Print the changes a command would make, without making them.")
+	fs.BoolVar(&assumeYes, "yes", false, "Do not ask for confirmation before destructive actions.")
+	fs.StringVar(&profile, "profile", "", "The configuration `profile` to use.")
+	fs.StringVar(&account, "account", "", "The `name` of the account to use, keeping a separate login for each one.")
+}
+
+// listFlags registers the options of resumable paged listings.
+func listFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&resume, "resume", false, "Continue an interrupted listing, saving progress to resume it later.")
+	pageFlags(fs)
+}
+
+// pageFlags registers the options of paged listings.
+func pageFlags(fs *flag.FlagSet) {
+	maxResultsFlag(fs)
+	fs.Int64Var(&pageSize, "page-size", 50, "Number of `items` requested per API call.")
+	fs.StringVar(&fieldNames, "fields", "", "Comma separated `fields` of each item to request, like 'id,snippet/title'.")
+	columnFlags(fs)
+}
+
+// columnFlags registers the options that select and format the printed
+// columns of listings.
+func columnFlags(fs *flag.FlagSet) {
+	outputFlags(fs)
+	fs.StringVar(&outputTemplate, "format", "", "Print each item with a Go `template`, like '{{.Id}} {{.Snippet.Title}}'.")
+	fs.StringVar(&columnNames, "columns", "", "Comma separated `names` of the columns to print, like 'id,title'.")
+	fs.BoolVar(&rawValues, "raw", false, "Print counts, dates and durations as returned by the API. Always set when -o is not table.")
+	fs.BoolVar(&relativeDates, "relative", false, "Print dates relative to now, like '3 days ago'.")
+	fs.StringVar(&timeZone, "tz", "", "The time `zone` used to print dates, like 'America/Sao_Paulo'. Defaults to the local zone.")
+}
+
+func outputFlags(fs *flag.FlagSet) {
+	fs.StringVar(&outputFormat, "o", "table", "The output `format` of listings: "+strings.Join(ogle.Formats, ", ")+".")
+	fs.BoolVar(&noHeaders, "no-headers", false, "Do not print the header row.")
+}
+
+func maxResultsFlag(fs *flag.FlagSet) {
+	fs.IntVar(&maxResults, "max-results", 0, "Maximum number of `items` to list. Use 0 to list all.")
+}
+
+func channelFlag(fs *flag.FlagSet) {
+	fs.StringVar(&channel, "channel", "", "The `channel` to use: an ID, @handle or channel URL.")
+}
+
+func playlistFlag(fs *flag.FlagSet) {
+	fs.StringVar(&playlist, "playlist", "", "The `playlist` to use: an ID or playlist URL. Use - to read them from stdin.")
+}
+
+func videoFlag(fs *flag.FlagSet) {
+	fs.StringVar(&video, "video", "", "The `video` to edit: an ID or video URL. Use - to read them from stdin.")
+}
+
+func parallelFlag(fs *flag.FlagSet) {
+	fs.IntVar(&parallelism, "parallel", 4, "Number of `workers` issuing concurrent changes.")
+}
+
+// liveEditFlags registers the options to update a broadcast.
+func liveEditFlags(fs *flag.FlagSet) {
+	fs.StringVar(&videoTitle, "title", "", "The `title` of the video to update.")
+	fs.StringVar(&videoDescription, "desc", "", "The `description` of the video to update.")
+}
+
+// videoEditFlags registers the options to update a video.
+func videoEditFlags(fs *flag.FlagSet) {
+	liveEditFlags(fs)
+	fs.StringVar(&videoCategory, "category", "", "The `category` of the video: an ID or a name like 'Gaming', looked up in the categories of -region.")
+	fs.StringVar(&videoTags, "tags", "", "The list of `tags` separated by ','. Use +tag and -tag to add and remove tags, keeping the others.")
+}
+
+// videoStatusFlags registers the options to update the status, languages and
+// recording details of a video.
+func videoStatusFlags(fs *flag.FlagSet) {
+	fs.StringVar(&videoPrivacy, "privacy", "", "The privacy `status` of the video: private, unlisted or public.")
+	fs.StringVar(&videoPublishAt, "publish-at", "", "Schedule a private video to be published at `time`, in RFC 3339 format. Use 'none' to cancel.")
+	fs.StringVar(&videoLicense, "license", "", "The `license` of the video: youtube or creativeCommon.")
+	fs.Var(&videoEmbeddable, "embeddable", "Allow the video to be embedded in other sites.")
+	fs.Var(&videoPublicStats, "public-stats", "Show the video statistics on the watch page.")
+	fs.Var(&videoMadeForKids, "made-for-kids", "Declare the video as made for kids.")
+	fs.StringVar(&videoLanguage, "language", "", "The `language` of the title and description, like 'en' or 'pt-BR'.")
+	fs.StringVar(&videoAudioLanguage, "audio-language", "", "The `language` spoken in the video.")
+	fs.StringVar(&videoRecordingDate, "recorded", "", "The `date` the video was recorded, as 2006-01-02 or in RFC 3339 format.")
+	fs.StringVar(&videoLocation, "location", "", "The `latitude,longitude` where the video was recorded.")
+}
+
+func editorFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&videoEdit, "edit", false, "Edit the title, description and tags in $EDITOR.")
+}
+
+// optionalBool is a boolean flag that records if it was set, so that false
+// can be told apart from not given.
+type optionalBool struct {
+	set   bool
+	value bool
+}
+
+func (b *optionalBool) String() string {
+	if b == nil || !b.set {
+		return ""
+	}
+	return strconv.FormatBool(b.value)
+}
+
+func (b *optionalBool) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*b = optionalBool{set: true, value: v}
+	return nil
+}
+
+func (b *optionalBool) IsBoolFlag() bool { return true }
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ronoaldo/ogle"
+	"google.golang.org/api/youtube/v3"
+)
+
+// completionKinds maps the flags completed from the local cache to the kinds
+// of values cached for them.
+var completionKinds = map[string][]string{
+	"playlist": {"playlists"},
+	"video":    {"uploads", "broadcasts"},
+	"channel":  {"channels"},
+	"category": {"categories"},
+}
+
+// completionScript prints the completion script for the shell in args.
+func completionScript(args []string) {
+	if len(args) != 1 {
+		fatal(ogle.UsageError("expected one shell name: %s", strings.Join(ogle.CompletionShells, ", ")))
+	}
+	script, err := ogle.CompletionScript(args[0], "youtube")
+	if err != nil {
+		fatal(ogle.UsageError("%v", err))
+	}
+	fmt.Print(script)
+}
+
+func shellCompletions() []ogle.CompletionItem {
+	items := make([]ogle.CompletionItem, 0, len(ogle.CompletionShells))
+	for _, shell := range ogle.CompletionShells {
+		items = append(items, ogle.CompletionItem{Value: shell})
+	}
+	return items
+}
+
+// complete prints the completion candidates for the last word in args, given
+// the words before it.
+func complete(args []string) {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	for _, item := range completions(args[:len(args)-1], args[len(args)-1]) {
+		fmt.Println(item)
+	}
+}
+
+func completions(prev []string, cur string) []ogle.CompletionItem {
+	// Find the command and the flag set that applies to the current word.
+	var (
+		c     *command
+		words []string
+		fs    = flag.NewFlagSet("youtube", flag.ContinueOnError)
+	)
+	globalFlags(fs)
+	for i := 0; i < len(prev); i++ {
+		arg := prev[i]
+		if !strings.HasPrefix(arg, "-") {
+			words = append(words, arg)
+			if found, rest := lookupCommand(words); found != nil && len(rest) == 0 {
+				c, fs = found, found.flagSet()
+			}
+			continue
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if i == len(prev)-1 {
+			// The current word is the value of this flag.
+			if kinds, ok := completionKinds[name]; ok && fs.Lookup(name) != nil {
+				return ogle.FilterCompletions(cachedCompletions(kinds), cur)
+			}
+			if f := fs.Lookup(name); f != nil && !isBoolFlag(f) {
+				return nil
+			}
+		} else if f := fs.Lookup(name); f != nil && !isBoolFlag(f) {
+			i++
+		}
+	}
+
+	if name, _, ok := strings.Cut(cur, "="); ok && strings.HasPrefix(name, "-") {
+		kinds, ok := completionKinds[strings.TrimLeft(name, "-")]
+		if !ok {
+			return nil
+		}
+		items := cachedCompletions(kinds)
+		for i := range items {
+			items[i].Value = name + "=" + items[i].Value
+		}
+		return ogle.FilterCompletions(items, cur)
+	}
+	if strings.HasPrefix(cur, "-") {
+		return ogle.FilterCompletions(flagCompletions(fs), cur)
+	}
+	if c != nil && c.Complete != nil {
+		return ogle.FilterCompletions(c.Complete(), cur)
+	}
+	if len(words) > 0 && words[0] == "help" {
+		words = words[1:]
+	}
+	return ogle.FilterCompletions(commandCompletions(words), cur)
+}
+
+// commandCompletions returns the words that follow the given ones in command
+// names.
+func commandCompletions(words []string) []ogle.CompletionItem {
+	items := make([]ogle.CompletionItem, 0)
+	seen := make(map[string]bool)
+	prefix := strings.Join(words, " ")
+	for _, c := range commands {
+		parts := strings.Fields(c.Name)
+		if c.Hidden || len(parts) <= len(words) || strings.Join(parts[:len(words)], " ") != prefix {
+			continue
+		}
+		next := parts[len(words)]
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		item := ogle.CompletionItem{Value: next}
+		if len(parts) == len(words)+1 {
+			item.Description = c.Short
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// flagCompletions returns the flags registered in fs.
+func flagCompletions(fs *flag.FlagSet) []ogle.CompletionItem {
+	items := make([]ogle.CompletionItem, 0)
+	fs.VisitAll(func(f *flag.Flag) {
+		_, usage := flag.UnquoteUsage(f)
+		items = append(items, ogle.CompletionItem{Value: "-" + f.Name, Description: usage})
+	})
+	return items
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// cachedCompletions returns the values cached for the given kinds. Errors are
+// ignored, as completion must not fail.
+func cachedCompletions(kinds []string) []ogle.CompletionItem {
+	items := make([]ogle.CompletionItem, 0)
+	for _, kind := range kinds {
+		cached, _ := ogle.LoadCompletions("youtube", kind)
+		items = append(items, cached...)
+	}
+	return items
+}
+
+// rememberCompletions adds the listed values to the completion cache.
+func rememberCompletions(kind string, items []ogle.CompletionItem) {
+	if len(items) == 0 {
+		return
+	}
+	if err := ogle.MergeCompletions("youtube", kind, items); err != nil {
+		log.Printf("Unable to update completion cache: %v", err)
+	}
+}
+
+// refreshCompletions replaces the completion cache with your channels,
+// playlists, uploads and broadcasts.
+func refreshCompletions(yt *youtube.Service) {
+	opts := pageOptions()
+
+	chans, _, err := ogle.Collect[*youtube.ChannelListResponse](ctx,
+		yt.Channels.List([]string{"snippet,contentDetails"}).Mine(true).
+			Fields(ogle.ListFields("id", "snippet/title", "contentDetails/relatedPlaylists/uploads")),
+		channelItems, ogle.PageOptions{PageSize: pageSize})
+	if err != nil {
+		fatal(err)
+	}
+	channels := make([]ogle.CompletionItem, 0, len(chans))
+	playlists := make([]ogle.CompletionItem, 0)
+	for _, ch := range chans {
+		channels = append(channels, ogle.CompletionItem{Value: ch.Id, Description: ch.Snippet.Title})
+		playlists = append(playlists, ogle.CompletionItem{
+			Value:       ch.ContentDetails.RelatedPlaylists.Uploads,
+			Description: "Uploads from " + ch.Snippet.Title,
+		})
+	}
+
+	lists, _, err := ogle.Collect[*youtube.PlaylistListResponse](ctx,
+		yt.Playlists.List([]string{"snippet"}).Mine(true).Fields(ogle.ListFields("id", "snippet/title")),
+		playlistItems, opts)
+	if err != nil {
+		fatal(err)
+	}
+	for _, p := range lists {
+		playlists = append(playlists, ogle.CompletionItem{Value: p.Id, Description: p.Snippet.Title})
+	}
+
+	uploads := make([]ogle.CompletionItem, 0)
+	for _, ch := range chans {
+		items, _, err := ogle.Collect[*youtube.PlaylistItemListResponse](ctx,
+			yt.PlaylistItems.List([]string{"snippet,contentDetails"}).
+				PlaylistId(ch.ContentDetails.RelatedPlaylists.Uploads).
+				Fields(ogle.ListFields("snippet/title", "contentDetails/videoId")),
+			playlistItemItems, opts)
+		if err != nil {
+			fatal(err)
+		}
+		for _, item := range items {
+			uploads = append(uploads, ogle.CompletionItem{Value: item.ContentDetails.VideoId, Description: item.Snippet.Title})
+		}
+	}
+
+	lives, _, err := ogle.Collect[*youtube.LiveBroadcastListResponse](ctx,
+		yt.LiveBroadcasts.List([]string{"snippet"}).BroadcastStatus("all").Fields(ogle.ListFields("id", "snippet/title")),
+		liveBroadcastItems, opts)
+	if err != nil {
+		fatal(err)
+	}
+	broadcasts := make([]ogle.CompletionItem, 0, len(lives))
+	for _, item := range lives {
+		broadcasts = append(broadcasts, ogle.CompletionItem{Value: item.Id, Description: item.Snippet.Title})
+	}
+
+	cache := map[string][]ogle.CompletionItem{
+		"channels":   channels,
+		"playlists":  playlists,
+		"uploads":    uploads,
+		"broadcasts": broadcasts,
+	}
+	for kind, items := range cache {
+		if err := ogle.SaveCompletions("youtube", kind, items); err != nil {
+			fatal(fmt.Errorf("unable to save completion cache: %w", err))
+		}
+	}
+	log.Printf("Cached %d channels, %d playlists, %d uploads and %d broadcasts for completion.",
+		len(channels), len(playlists), len(uploads), len(broadcasts))
+}
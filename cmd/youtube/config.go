@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ronoaldo/ogle"
+)
+
+// Configuration command line options
+var (
+	profile string
+	account string
+	region  string
+)
+
+// setting is a key of the configuration file that provides the default value
+// of a command line option.
+type setting struct {
+	Key   string
+	Flag  string
+	Usage string
+	Value *string
+
+	// Check, if not nil, validates a non-empty value.
+	Check func(string) error
+}
+
+// settings are the keys accepted in the configuration file.
+var settings = []setting{
+	{Key: "account", Flag: "account", Usage: "name of the account used to log in", Value: &account},
+	{Key: "channel", Flag: "channel", Usage: "default channel id", Value: &channel},
+	{Key: "output", Flag: "o", Usage: "output format of listings", Value: &outputFormat, Check: checkOutputFormat},
+	{Key: "timezone", Flag: "tz", Usage: "time zone used to print dates", Value: &timeZone, Check: checkTimeZone},
+	{Key: "region", Flag: "region", Usage: "region code used to list video categories", Value: &region},
+}
+
+// Configuration state
+var (
+	config         *ogle.Config
+	setFlags       = make(map[string]bool)
+	settingSources = make(map[string]string)
+)
+
+// settingEnv returns the environment variable that overrides the key.
+func settingEnv(key string) string {
+	return "YOUTUBE_" + strings.ToUpper(key)
+}
+
+func checkOutputFormat(v string) error {
+	for _, f := range ogle.Formats {
+		if v == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %q, use one of: %s", v, strings.Join(ogle.Formats, ", "))
+}
+
+func checkTimeZone(v string) error {
+	if _, err := time.LoadLocation(v); err != nil {
+		return fmt.Errorf("invalid time zone %q: %v", v, err)
+	}
+	return nil
+}
+
+func lookupSetting(key string) (setting, bool) {
+	for _, s := range settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return setting{}, false
+}
+
+// applySettings loads the configuration file and sets the options that were
+// not given on the command line. Environment variables override both the
+// configuration file and the command line.
+//
+// Invalid values only print a warning for the config commands, so a broken
+// configuration file can still be fixed with them.
+func applySettings(c *command) {
+	offline := strings.HasPrefix(c.Name, "config ")
+	var err error
+	if config, err = ogle.LoadConfig("youtube"); err != nil {
+		fatal(err)
+	}
+	if v := os.Getenv(settingEnv("profile")); v != "" {
+		profile = v
+	}
+	if profile != "" && !config.HasProfile(profile) && c.Name != "config set" {
+		if len(config.Profiles) == 0 {
+			fatal(ogle.UsageError("unknown profile %q, create it with 'youtube config set -profile %s'", profile, profile))
+		}
+		fatal(ogle.UsageError("unknown profile %q, use one of: %s",
+			profile, strings.Join(config.ProfileNames(), ", ")))
+	}
+
+	for _, s := range settings {
+		def := *s.Value
+		if v := os.Getenv(settingEnv(s.Key)); v != "" {
+			*s.Value = v
+			settingSources[s.Key] = "env " + settingEnv(s.Key)
+		} else if setFlags[s.Flag] {
+			settingSources[s.Key] = "flag -" + s.Flag
+			continue
+		} else if v, ok := config.Get(profile, s.Key); ok {
+			*s.Value = v
+			if _, inProfile := config.Profiles[profile][s.Key]; inProfile && profile != "" {
+				settingSources[s.Key] = "profile " + profile
+			} else {
+				settingSources[s.Key] = "config"
+			}
+		}
+		if s.Check == nil || *s.Value == "" || !offline {
+			continue
+		}
+		if err := s.Check(*s.Value); err != nil {
+			log.Printf("Warning: ignoring %s from %s: %v", s.Key, settingSources[s.Key], err)
+			*s.Value = def
+			delete(settingSources, s.Key)
+		}
+	}
+	ogle.SetAccount("youtube", account)
+}
+
+func settingCompletions() []ogle.CompletionItem {
+	items := make([]ogle.CompletionItem, 0, len(settings))
+	for _, s := range settings {
+		items = append(items, ogle.CompletionItem{Value: s.Key, Description: s.Usage})
+	}
+	return items
+}
+
+func configList() {
+	defer closeOutput()
+	if !noHeaders {
+		w.Header("KEY", "VALUE", "SOURCE")
+	}
+	for _, s := range settings {
+		source := settingSources[s.Key]
+		if source == "" {
+			source = "default"
+		}
+		v := map[string]string{"key": s.Key, "value": *s.Value, "source": source}
+		w.Row(v, s.Key, *s.Value, source)
+	}
+}
+
+func configGet(args []string) {
+	if len(args) != 1 {
+		fatal(ogle.UsageError("expected one key"))
+	}
+	s, ok := lookupSetting(args[0])
+	if !ok {
+		fatal(unknownSetting(args[0]))
+	}
+	fmt.Println(*s.Value)
+}
+
+func configSet(args []string) {
+	if len(args) != 2 {
+		fatal(ogle.UsageError("expected a key and a value"))
+	}
+	s, ok := lookupSetting(args[0])
+	if !ok {
+		fatal(unknownSetting(args[0]))
+	}
+	if s.Check != nil && args[1] != "" {
+		if err := s.Check(args[1]); err != nil {
+			fatal(ogle.UsageError("%v", err))
+		}
+	}
+	config.Set(profile, args[0], args[1])
+	if err := ogle.SaveConfig("youtube", config); err != nil {
+		fatal(fmt.Errorf("unable to save config: %w", err))
+	}
+}
+
+func unknownSetting(key string) error {
+	keys := make([]string, 0, len(settings))
+	for _, s := range settings {
+		keys = append(keys, s.Key)
+	}
+	return ogle.UsageError("unknown key %q, use one of: %s", key, strings.Join(keys, ", "))
+}
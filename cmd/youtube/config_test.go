@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestSettingChecks(t *testing.T) {
+	tests := []struct {
+		key, value string
+		valid      bool
+	}{
+		{"output", "json", true},
+		{"output", "yaml", true},
+		{"output", "JSON", false},
+		{"output", "xml", false},
+		{"timezone", "UTC", true},
+		{"timezone", "America/Sao_Paulo", true},
+		{"timezone", "Mars/Base", false},
+		{"timezone", "GMT+3", false},
+	}
+	for _, tt := range tests {
+		s, ok := lookupSetting(tt.key)
+		if !ok || s.Check == nil {
+			t.Fatalf("setting %q has no check", tt.key)
+		}
+		if err := s.Check(tt.value); (err == nil) != tt.valid {
+			t.Errorf("%s %q: got error %v, want valid %v", tt.key, tt.value, err, tt.valid)
+		}
+	}
+}
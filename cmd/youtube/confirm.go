@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/ronoaldo/ogle"
+)
+
+// stdoutMu serializes the output of concurrent previews.
+var stdoutMu sync.Mutex
+
+// previewOutput returns where dry-run changes are printed: the standard
+// output only for table output, so they do not break the CSV, JSON or
+// template output of a batch.
+func previewOutput() io.Writer {
+	if outputFormat == "table" && outputTemplate == "" {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+// preview reports the changes to be made to a resource. It returns the
+// outcome when the update must be skipped: "unchanged" if nothing changed, or
+// "dry-run" if -dry-run is set, in which case the diff is printed. An empty
+// string means the update must proceed. The diff is printed to
+// previewOutput.
+func preview(kind, id string, changes ogle.Changes) string {
+	if len(changes) == 0 {
+		log.Printf("Nothing to update in %s (id=%s)", kind, id)
+		return "unchanged"
+	}
+	if !dryRun {
+		return ""
+	}
+	stdoutMu.Lock()
+	defer stdoutMu.Unlock()
+	out := previewOutput()
+	fmt.Fprintf(out, "%s %s\n", kind, id)
+	if err := changes.Print(out); err != nil {
+		fatal(err)
+	}
+	log.Printf("Dry run: %s (id=%s) was not updated", kind, id)
+	return "dry-run"
+}
+
+// confirm asks the user to confirm a destructive action, exiting if the answer
+// is not yes. No question is asked when -yes is set.
+func confirm(question string) {
+	if assumeYes {
+		return
+	}
+	ok, err := ogle.Confirm(os.Stdin, os.Stderr, question)
+	if err != nil {
+		fatal(err)
+	}
+	if !ok {
+		fatal(ogle.NewFailure("aborted", errors.New("the operation was not confirmed")))
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ronoaldo/ogle"
+	"golang.org/x/net/context"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Editor command line options
+var (
+	videoEdit bool
+)
+
+// editSeparator is the line between the header and the description in the
+// file opened in the editor.
+const editSeparator = "---"
+
+// formatEditFile returns the text opened in the editor for the snippet.
+func formatEditFile(id string, s *youtube.VideoSnippet) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Editing video %s.\n", id)
+	fmt.Fprintf(&b, "# Change the title and tags below, and the description after the %s line.\n", editSeparator)
+	b.WriteString("# Lines starting with '#' above the separator are ignored. Save and\n")
+	b.WriteString("# close the editor to apply the changes, or clear the file to cancel.\n")
+	fmt.Fprintf(&b, "Title: %s\n", s.Title)
+	fmt.Fprintf(&b, "Tags: %s\n", strings.Join(s.Tags, ", "))
+	b.WriteString(editSeparator + "\n")
+	b.WriteString(s.Description)
+	if !strings.HasSuffix(s.Description, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// parseEditFile reads the title, description and tags from the edited text.
+// It returns nil if the file is empty, to cancel the edit.
+func parseEditFile(text string) (*youtube.VideoSnippet, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	header, desc, ok := strings.Cut(text, "\n"+editSeparator+"\n")
+	if !ok {
+		if !strings.HasSuffix(text, "\n"+editSeparator) {
+			return nil, fmt.Errorf("missing the %s line before the description", editSeparator)
+		}
+		header = strings.TrimSuffix(text, "\n"+editSeparator)
+	}
+	s := &youtube.VideoSnippet{Description: strings.TrimSuffix(desc, "\n")}
+	for n, line := range strings.Split(header, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected Title: or Tags:, got %q", n+1, line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			s.Title = value
+		case "tags":
+			s.Tags = ogle.DedupTags(strings.Split(value, ","))
+		default:
+			return nil, fmt.Errorf("line %d: unknown field %q, use Title or Tags", n+1, key)
+		}
+	}
+	if s.Title == "" {
+		return nil, fmt.Errorf("the title cannot be empty")
+	}
+	return s, nil
+}
+
+// editVideo opens the title, description and tags of the video in the
+// editor, updating the fields that were changed.
+func editVideo(ctx context.Context, yt *youtube.Service, id string) (string, error) {
+	parts := []string{"id,snippet"}
+	resp, err := yt.Videos.List(parts).Id(id).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Items) == 0 {
+		return "", ogle.NewFailure("videoNotFound", fmt.Errorf("No vídeos matched the provided id '%s'", id))
+	}
+	videoPayload := resp.Items[0]
+	snippet := videoPayload.Snippet
+
+	text, err := ogle.EditText(formatEditFile(id, snippet), "video-"+id+"-*.txt")
+	if err != nil {
+		return "", err
+	}
+	edited, err := parseEditFile(text)
+	if err != nil {
+		return "", ogle.UsageError("invalid edit: %v", err)
+	}
+	if edited == nil {
+		log.Printf("Empty file, video %s was not updated", id)
+		return "unchanged", nil
+	}
+	if err := (ogle.VideoMetadata{Title: edited.Title, Description: edited.Description, Tags: edited.Tags}).Validate(); err != nil {
+		return "", err
+	}
+
+	changes := ogle.Changes{}
+	changes.Add("title", snippet.Title, edited.Title)
+	changes.Add("description", strings.TrimSuffix(snippet.Description, "\n"), edited.Description)
+	changes.AddList("tags", snippet.Tags, edited.Tags)
+	for _, c := range changes {
+		switch c.Field {
+		case "title":
+			snippet.Title = edited.Title
+		case "description":
+			snippet.Description = edited.Description
+		case "tags":
+			snippet.Tags = edited.Tags
+		}
+	}
+	if skip := preview("video", id, changes); skip != "" {
+		return skip, nil
+	}
+
+	log.Printf("Updating video (id=%s). Updated fields: %s", id, strings.Join(changes.Fields(), ", "))
+	if _, err := yt.Videos.Update(parts, videoPayload).Context(ctx).Do(); err != nil {
+		return "", fmt.Errorf("error updating video: %w", err)
+	}
+	log.Println("Vídeo updated")
+	return "updated", nil
+}
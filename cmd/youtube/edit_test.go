@@ -0,0 +1,113 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestParseEditFile(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want *youtube.VideoSnippet
+		err  string
+	}{
+		{
+			name: "empty file cancels",
+			text: " \n\n",
+		},
+		{
+			name: "all fields",
+			text: "# comment\nTitle: Live coding: part 1\nTags: go, live coding , Go\n---\nFirst line\n\nSecond line\n",
+			want: &youtube.VideoSnippet{
+				Title:       "Live coding: part 1",
+				Tags:        []string{"go", "live coding"},
+				Description: "First line\n\nSecond line",
+			},
+		},
+		{
+			name: "separator inside the description",
+			text: "Title: t\nTags: a\n---\nIntro\n---\nChapters\n---\n",
+			want: &youtube.VideoSnippet{
+				Title:       "t",
+				Tags:        []string{"a"},
+				Description: "Intro\n---\nChapters\n---",
+			},
+		},
+		{
+			name: "empty description",
+			text: "Title: t\nTags:\n---",
+			want: &youtube.VideoSnippet{Title: "t", Tags: []string{}},
+		},
+		{
+			name: "windows line endings",
+			text: "title: t\r\nTAGS: a,b\r\n---\r\nd\r\n",
+			want: &youtube.VideoSnippet{Title: "t", Tags: []string{"a", "b"}, Description: "d"},
+		},
+		{
+			name: "hash in the description is kept",
+			text: "Title: t\n---\n# Chapters\n#go\n",
+			want: &youtube.VideoSnippet{Title: "t", Description: "# Chapters\n#go"},
+		},
+		{
+			name: "missing separator",
+			text: "Title: t\nTags: a\n",
+			err:  "missing the --- line",
+		},
+		{
+			name: "empty title",
+			text: "Title:\n---\nd\n",
+			err:  "the title cannot be empty",
+		},
+		{
+			name: "unknown field",
+			text: "# c\nTitle: t\nCategory: 22\n---\n",
+			err:  `line 3: unknown field "Category"`,
+		},
+		{
+			name: "not a field",
+			text: "Title: t\njust text\n---\n",
+			err:  `line 2: expected Title: or Tags:`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEditFile(tt.text)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("parseEditFile: %v, want error %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEditFile: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEditFile:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditFileRoundTrip(t *testing.T) {
+	s := &youtube.VideoSnippet{
+		Title:       "Episode #3: YAML",
+		Tags:        []string{"go", "live coding"},
+		Description: "Links:\n---\n# not a comment\n",
+	}
+	got, err := parseEditFile(formatEditFile("abc", s))
+	if err != nil {
+		t.Fatalf("parseEditFile: %v", err)
+	}
+	want := &youtube.VideoSnippet{
+		Title:       s.Title,
+		Tags:        s.Tags,
+		Description: strings.TrimSuffix(s.Description, "\n"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -1,47 +1,108 @@
 // Command youtube allows you to interact with the Youtube service using a
 // command line interface.
 //
-// Check the updated help with "youtube --help":
+// Each command has its own flags. Check the updated help with "youtube help"
+// or "youtube help <command>":
 //
-//	Usage of youtube:
-//	-category category_id
-//		The category_id of the video to update.
-//	-channel channel_id
-//		The channel_id id to use.
-//	-cmd command
-//		The command to execute. Use -cmd="list" to show all commands. (default "list")
-//	-desc description
-//		The description of the video to update.
-//	-playlist playlist_id
-//		The playlist_id to use.
-//	-tags tags
-//		The list of tags separated by ',' to be used in the updated video.
-//	-title title
-//		The title of the video to update.
-//	-video video_id
-//		The video_id to use for editing.
+//	Usage: youtube <command> [flags]
 //
-//	Use one of the following values for the -cmd parameter:
-//		channels        list channels
-//		subscribers     list subscribers
-//		playlists       list playlists
-//		playlist-items  list videos in playlist
-//		playlist-dedup  remove duplicate videos from playlist
-//		video-update    update details about a video
-//		lives           list upcomming broadcasts
-//		list,help       show this message
-//		logout          revoke credentials
+//	Commands:
+//	  channels list        list your channels
+//	  subscribers list     list the subscribers of your channel
+//	  playlists list       list playlists of your channel or of -channel
+//	  playlists items      list videos in a playlist
+//	  playlists dedup      remove duplicate videos from a playlist
+//	  videos update        update details about a video
+//	  videos upload        upload a video file
+//	  videos upload-watch  upload the videos dropped in a directory
+//	  thumbnails set       set the custom thumbnail of a video
+//	  thumbnails get       download the thumbnails of videos
+//	  thumbnails render    render a thumbnail from a template
+//	  captions list        list the caption tracks of a video
+//	  captions download    download a caption track as SRT, WebVTT or SBV
+//	  captions upload      upload or replace a caption track
+//	  captions delete      delete a caption track
+//	  captions convert     convert caption files between SRT, WebVTT and SBV
+//	  categories list      list the video categories of a region
+//	  lives list           list upcoming and past broadcasts
+//	  lives update         update title and description of a broadcast
+//	  config list          list the settings and where their values come from
+//	  config get           print the value of a setting
+//	  config set           change a setting in the configuration file
+//	  cache clear          remove cached API responses
+//	  logout               revoke credentials
+//	  help                 show help about all commands or a single one
+//	  completion           print the shell completion script
+//	  completion refresh   cache your playlists, uploads and broadcasts for completion
 //
+// The flags shared by all commands, like -cache and -errors, can also be given
+// before the command name. The old -cmd flag, like -cmd=playlist-items, is
+// still accepted but deprecated.
+//
+// Default values for the account, channel, output format, time zone and region
+// are read from the configuration file, config.json, under the ogle config dir,
+// like ~/.config/ogle/youtube on Linux. Settings can be grouped in named
+// profiles, selected with -profile, and are managed with the "config"
+// commands:
+//
+//	youtube config set output json
+//	youtube config set -profile work account work
+//	youtube config set -profile work channel UC_x5XG1OV2P6uZZ5FSM9Ttw
+//	youtube playlists list -profile work
+//
+// Command line flags override the configuration file. Environment variables,
+// like YOUTUBE_CHANNEL, YOUTUBE_OUTPUT or YOUTUBE_PROFILE, override both.
+//
+// Videos are uploaded with "videos upload", which shows the progress and can
+// continue an interrupted upload with -resume:
+//
+//	youtube videos upload -title "Live coding #42" -playlist PLxxxx video.mp4
+//
+// Metadata can also be read from a JSON or YAML sidecar file next to the video,
+// like video.mp4.yaml, which records the ID of the uploaded video. With
+// "videos upload-watch", the videos dropped in a directory are uploaded and
+// moved to its done or failed subdirectories.
+//
+// The -video, -playlist and -channel flags accept IDs or any YouTube URL, like
+// https://youtu.be/VIDEO_ID, https://www.youtube.com/watch?v=VIDEO_ID&list=ID
+// or studio links. Channels can also be given by @handle or custom URL, which
+// are resolved with the API.
+//
+// Commands that change videos or playlists also accept - as the ID, reading
+// IDs, URLs or JSON Lines records from the standard input and printing one
+// result per input, so they can be combined with other commands:
+//
+//	youtube playlists items -playlist PLxxxx -columns id -no-headers |
+//		youtube videos update -video - -tags "music,80s"
+//
+// Commands that change your channel, like "videos update" or "playlists
+// dedup", accept -dry-run to print the changes they would make, as a diff of
+// the updated fields or the list of items to delete, without making them.
+// Destructive actions ask for confirmation unless -yes is given.
+//
+// The command exits with one of the following status codes, so scripts can
+// branch on the kind of failure:
+//
+//	0  success
+//	1  unclassified error
+//	2  invalid command line arguments
+//	3  missing, expired or revoked credentials
+//	4  operation not allowed for the user
+//	5  resource not found
+//	6  quota or rate limit exceeded
+//	7  request rejected as invalid by the API
 package main
 
 import (
 	"encoding/json"
-	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ronoaldo/ogle"
 	"golang.org/x/net/context"
@@ -50,7 +111,6 @@ import (
 
 // General command line options
 var (
-	command  string
 	playlist string
 	channel  string
 	video    string
@@ -64,216 +124,441 @@ var (
 	videoTags        string
 )
 
-// Globals
+// Video status command line options
 var (
-	w   = ogle.NewTabWriter(os.Stdout)
-	ctx = context.Background()
+	videoPublishAt     string
+	videoLicense       string
+	videoEmbeddable    optionalBool
+	videoPublicStats   optionalBool
+	videoMadeForKids   optionalBool
+	videoLanguage      string
+	videoAudioLanguage string
+	videoRecordingDate string
+	videoLocation      string
 )
 
-func init() {
-	flag.StringVar(&command, "cmd", "list", "The `command` to execute. Use -cmd=\"list\" to show all commands.")
-	flag.StringVar(&playlist, "playlist", "", "The `playlist_id` to use.")
-	flag.StringVar(&channel, "channel", "", "The `channel_id` id to use.")
-	flag.StringVar(&video, "video", "", "The `video_id` to use for editing.")
-	flag.StringVar(&videoTitle, "title", "", "The `title` of the video to update.")
-	flag.StringVar(&videoDescription, "desc", "", "The `description` of the video to update.")
-	flag.StringVar(&videoCategory, "category", "", "The `category_id` of the video to update.")
-	flag.StringVar(&videoTags, "tags", "", "The list of `tags` separated by ',' to be used in the updated video.")
-}
+// Listing command line options
+var (
+	maxResults int
+	pageSize   int64 = 50
+	resume     bool
+)
+
+// Cache command line options
+var (
+	useCache     bool
+	cacheTTL     time.Duration
+	cacheMaxSize int64 = 50
+)
+
+// Output command line options
+var (
+	outputFormat   = "table"
+	outputTemplate string
+	columnNames    string
+	noHeaders      bool
+	fieldNames     string
+	rawValues      bool
+	relativeDates  bool
+	timeZone       string
+)
+
+// Confirmation command line options
+var (
+	dryRun    bool
+	assumeYes bool
+)
+
+// Error reporting command line options
+var (
+	errorFormat = "text"
+)
+
+// Concurrency command line options
+var (
+	rateLimit   float64
+	rateBurst   = 1
+	parallelism = 4
+)
+
+// Globals
+var (
+	httpClient *http.Client
+	w          ogle.Formatter
+	human      ogle.Humanizer
+	ctx        = context.Background()
+)
 
 func main() {
-	flag.Parse()
+	c := parseCommandLine(os.Args[1:])
+	if len(commandArgs) > 0 && !c.Positional && !c.Raw {
+		fatal(ogle.UsageError("unexpected arguments: %v", strings.Join(commandArgs, " ")))
+	}
+	if !c.Raw {
+		applySettings(c)
+	}
+
+	var err error
+	if outputTemplate != "" {
+		w, err = ogle.NewTemplateFormatter(os.Stdout, outputTemplate)
+	} else {
+		w, err = ogle.NewFormatter(outputFormat, os.Stdout)
+	}
+	if err != nil {
+		fatal(ogle.UsageError("%v", err))
+	}
+	// Only tables are meant to be read by people; other formats keep the
+	// values as returned by the API, so they can be processed.
+	human = ogle.Humanizer{Raw: rawValues || outputFormat != "table", Relative: relativeDates}
+	if timeZone != "" {
+		if human.Location, err = time.LoadLocation(timeZone); err != nil {
+			fatal(ogle.UsageError("invalid time zone %q: %v", timeZone, err))
+		}
+	}
+
+	if c.Offline {
+		c.Run(nil)
+		return
+	}
+	c.Run(newService())
+}
 
+// newService creates the authorized API client and resolves the IDs given in
+// the command line.
+func newService() *youtube.Service {
 	client, err := ogle.NewClient(ctx, "youtube", youtube.YoutubeScope)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
+	}
+	// Responses served from the cache do not count against the rate limit.
+	ogle.SetRateLimit("youtube", rateLimit, rateBurst)
+	client.Transport = ogle.NewRateLimitTransport("youtube", client.Transport)
+	if useCache {
+		t := ogle.NewCacheTransport("youtube", client.Transport)
+		t.TTL = cacheTTL
+		t.MaxSize = cacheMaxSize * 1024 * 1024
+		client.Transport = t
 	}
+	httpClient = client
 
 	yt, err := youtube.New(client)
 	if err != nil {
-		log.Fatal(err)
-	}
-
-	switch command {
-	case "channels":
-		listChannels(yt)
-	case "subscribers", "subs":
-		listSubscribers(yt)
-	case "playlists":
-		listPlaylists(yt)
-	case "playlist-videos", "playlist-items":
-		listPlaylistVideos(yt)
-	case "playlist-dedup", "dedup-playlist":
-		removeDuplicatesFromPlaylist(yt)
-	case "video-update":
-		videoUpdate(yt)
-	case "lives":
-		listLives(yt)
-	case "live-update":
-		updateLive(yt)
-	case "reauth", "logout":
-		logout()
-	case "list", "help":
-		listCommands()
-	default:
-		log.Printf("Unknown command: '%s'", command)
-		listCommands()
+		fatal(err)
+	}
+	resolveIDs(yt)
+	return yt
+}
+
+// resolveIDs replaces the URLs and handles given to -video, -playlist and
+// -channel with the IDs they reference.
+func resolveIDs(yt *youtube.Service) {
+	var err error
+	if video != "" && video != stdinArg {
+		if video, err = ogle.ParseVideoID(video); err != nil {
+			fatal(ogle.UsageError("invalid -video: %v", err))
+		}
+	}
+	if playlist != "" && playlist != stdinArg {
+		if playlist, err = ogle.ParsePlaylistID(playlist); err != nil {
+			fatal(ogle.UsageError("invalid -playlist: %v", err))
+		}
+	}
+	if channel != "" {
+		if channel, err = ogle.ResolveChannelID(ctx, yt, channel); err != nil {
+			fatal(err)
+		}
+	}
+}
+
+// fatal reports the error in the format selected with -errors and exits with
+// the status code that matches the kind of failure.
+func fatal(err error) {
+	if w != nil {
+		w.Flush()
+	}
+	f := ogle.Explain(err)
+	if errorFormat == "json" {
+		json.NewEncoder(os.Stderr).Encode(f)
+	} else {
+		log.Printf("Error: %v", f.Message)
+		if f.Hint != "" {
+			log.Printf("Hint: %v", f.Hint)
+		}
+	}
+	os.Exit(f.ExitCode)
+}
+
+// closeOutput closes the output formatter, reporting errors like a failed
+// write or a -format template that does not apply to the listed resources.
+func closeOutput() {
+	if err := w.Close(); err != nil {
+		fatal(err)
 	}
 }
 
-var cmdList = `
-Use one of the following values for the -cmd parameter:
-	channels	list channels
-	subscribers	list subscribers
-	playlists	list playlists
-	playlist-items	list videos in playlist
-	playlist-dedup	remove duplicate videos from playlist
-	video-update	update details about a video
-	lives		list upcomming broadcasts
-	list,help	show this message
-	logout		revoke credentials
-`
+func pageOptions() ogle.PageOptions {
+	return ogle.PageOptions{
+		Limit:    maxResults,
+		PageSize: pageSize,
+	}
+}
+
+// resumable returns the page options for the named listing. When -resume is
+// set, the listing continues from the last saved checkpoint, restoring count to
+// the number of items already listed, and progress is saved after each page.
+// The returned function must be called with the final cursor.
+func resumable(name string, count *int) (ogle.PageOptions, func(*ogle.Cursor)) {
+	opts := pageOptions()
+	if !resume {
+		return opts, func(*ogle.Cursor) {}
+	}
+
+	file := ogle.CheckpointFile("youtube", name)
+	params := fmt.Sprintf("%s|channel=%s|playlist=%s|page-size=%d", name, channel, playlist, pageSize)
+	cp, err := ogle.LoadCheckpoint(file, params)
+	if err != nil {
+		fatal(fmt.Errorf("unable to load checkpoint: %w", err))
+	}
+	if cp != nil {
+		log.Printf("Resuming %s after %d items", name, cp.Count)
+		opts.Start = cp.Cursor
+		*count = cp.Count
+	}
 
-func listCommands() {
-	flag.Usage()
-	fmt.Fprintf(os.Stdout, cmdList)
+	save := func(next *ogle.Cursor) error {
+		// Items must be written before they are recorded as processed.
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if next == nil {
+			return ogle.RemoveCheckpoint(file)
+		}
+		return ogle.SaveCheckpoint(file, &ogle.Checkpoint{Params: params, Cursor: *next, Count: *count})
+	}
+	opts.OnPage = save
+	return opts, func(next *ogle.Cursor) {
+		if err := save(next); err != nil {
+			fatal(fmt.Errorf("unable to save checkpoint: %w", err))
+		}
+	}
 }
 
 func listChannels(yt *youtube.Service) {
 	count := 0
-	w.Println("#", "ID", "NAME", "LANGUAGE", "URL", "SUBSCRIBERS", "VIDEOS", "UPLOADS_PLAYLIST", "VIEWS")
-	defer w.Flush()
+	opts, checkpoint := resumable("channels", &count)
+	cols := selectColumns(channelColumns, count)
+	defer closeOutput()
 	req := yt.Channels.List([]string{"id,snippet,statistics,contentDetails"}).Mine(true)
-	err := req.Pages(ctx, func(resp *youtube.ChannelListResponse) error {
-		for i := range resp.Items {
-			ch := resp.Items[i]
-			count++
-			w.Println(count, ch.Id, ch.Snippet.Title, ch.Snippet.DefaultLanguage, ch.Snippet.CustomUrl,
-				ch.Statistics.SubscriberCount, ch.Statistics.VideoCount,
-				ch.ContentDetails.RelatedPlaylists.Uploads, ch.Statistics.ViewCount)
-		}
+	if mask := fieldsMask(cols); mask != "" {
+		req.Fields(mask)
+	}
+	next, err := ogle.Paginate[*youtube.ChannelListResponse](ctx, req, channelItems, opts, func(ch *youtube.Channel) error {
+		count++
+		w.Row(ch, cols.Values(count, ch)...)
 		return nil
 	})
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
+	checkpoint(next)
 }
 
 func listSubscribers(yt *youtube.Service) {
 	count := 0
-	w.Println("#", "NAME", "DESCRIPTION", "URL")
-	defer w.Flush()
+	opts, checkpoint := resumable("subscribers", &count)
+	cols := selectColumns(subscriptionColumns, count)
+	defer closeOutput()
 	req := yt.Subscriptions.List([]string{"subscriberSnippet"}).MySubscribers(true).Order("alphabetical")
-	err := req.Pages(context.Background(), func(resp *youtube.SubscriptionListResponse) error {
-		for _, sub := range resp.Items {
-			count++
-			desc := substr(sub.SubscriberSnippet.Description, 0, 40)
-			url := "https://www.youtube.com/channel/" + sub.SubscriberSnippet.ChannelId
-			w.Println(count, sub.SubscriberSnippet.Title, strings.Split(desc, "\n")[0], url)
-		}
+	if mask := fieldsMask(cols); mask != "" {
+		req.Fields(mask)
+	}
+	next, err := ogle.Paginate[*youtube.SubscriptionListResponse](ctx, req, subscriptionItems, opts, func(sub *youtube.Subscription) error {
+		count++
+		w.Row(sub, cols.Values(count, sub)...)
 		return nil
 	})
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
+	checkpoint(next)
 }
 
 func listPlaylists(yt *youtube.Service) {
 	count := 0
-	w.Println("#", "ID", "NAME", "CHANNEL", "VISIBILITY", "VIDEOS")
-	defer w.Flush()
+	opts, checkpoint := resumable("playlists", &count)
+	cols := selectColumns(playlistColumns, count)
+	defer closeOutput()
 
 	req := yt.Playlists.List([]string{"id,snippet,status,contentDetails"})
+	if mask := fieldsMask(cols); mask != "" {
+		req.Fields(mask)
+	}
 	if channel != "" {
 		req.ChannelId(channel)
 	} else {
 		req.Mine(true)
 	}
 
-	err := req.Pages(context.Background(), func(resp *youtube.PlaylistListResponse) error {
-		for _, p := range resp.Items {
-			count++
-			w.Println(count, p.Id, p.Snippet.Title, p.Snippet.ChannelTitle,
-				p.Status.PrivacyStatus, p.ContentDetails.ItemCount)
+	seen := make([]ogle.CompletionItem, 0)
+	next, err := ogle.Paginate[*youtube.PlaylistListResponse](ctx, req, playlistItems, opts, func(p *youtube.Playlist) error {
+		count++
+		w.Row(p, cols.Values(count, p)...)
+		if channel == "" && p.Snippet != nil && p.Snippet.Title != "" {
+			seen = append(seen, ogle.CompletionItem{Value: p.Id, Description: p.Snippet.Title})
 		}
 		return nil
 	})
+	rememberCompletions("playlists", seen)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
+	checkpoint(next)
 }
 
 func listPlaylistVideos(yt *youtube.Service) {
 	if playlist == "" {
-		log.Fatal("You must spefify a playlist with `-playlist` argument.")
+		fatal(ogle.UsageError("You must spefify a playlist with `-playlist` argument."))
 	}
 	count := 0
-
-	w.Println("#", "PUBLISHED_AT", "VIDEO", "STATUS", "URL")
-	defer w.Flush()
+	opts, checkpoint := resumable("playlist-items", &count)
+	cols := selectColumns(playlistItemColumns, count)
+	defer closeOutput()
 
 	req := yt.PlaylistItems.List([]string{"id,snippet,status,contentDetails"}).PlaylistId(playlist)
+	if mask := fieldsMask(cols); mask != "" {
+		req.Fields(mask)
+	}
 
-	err := req.Pages(context.Background(), func(resp *youtube.PlaylistItemListResponse) error {
-		for _, item := range resp.Items {
-			count++
-			url := "https://youtu.be/" + item.ContentDetails.VideoId
-			w.Println(count, item.Snippet.PublishedAt, item.Snippet.Title, item.Status.PrivacyStatus, url)
+	items := playlistItemItems
+	if hasColumn(cols, "duration") {
+		// Playlist items have no duration, so it is fetched for each page
+		// before its items are printed.
+		items = func(r *youtube.PlaylistItemListResponse) []*youtube.PlaylistItem {
+			if err := fetchDurations(yt, r.Items); err != nil {
+				fatal(err)
+			}
+			return r.Items
 		}
+	}
+
+	next, err := ogle.Paginate[*youtube.PlaylistItemListResponse](ctx, req, items, opts, func(item *youtube.PlaylistItem) error {
+		count++
+		w.Row(item, cols.Values(count, item)...)
 		return nil
 	})
 
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
+	checkpoint(next)
+}
+
+// fetchDurations stores the duration of the videos in the playlist items in
+// videoDurations. Up to 50 items, the size of a page, are looked up at once.
+func fetchDurations(yt *youtube.Service, items []*youtube.PlaylistItem) error {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.ContentDetails != nil && item.ContentDetails.VideoId != "" {
+			ids = append(ids, item.ContentDetails.VideoId)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	resp, err := yt.Videos.List([]string{"contentDetails"}).Id(ids...).
+		Fields("items(id,contentDetails/duration)").Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("unable to fetch video durations: %w", err)
+	}
+	for _, v := range resp.Items {
+		videoDurations[v.Id] = v.ContentDetails.Duration
+	}
+	return nil
 }
 
 func removeDuplicatesFromPlaylist(yt *youtube.Service) {
+	if playlist == stdinArg {
+		if !assumeYes && !dryRun {
+			fatal(ogle.UsageError("-yes or -dry-run is required when reading playlists from stdin"))
+		}
+		runBatch("playlist", ogle.ParsePlaylistID, func(ctx context.Context, in ogle.Input, id string) (string, error) {
+			return dedupPlaylist(ctx, yt, id)
+		})
+		return
+	}
+	if playlist == "" {
+		fatal(ogle.UsageError("You must specify a playlist with `-playlist` argument."))
+	}
+	if _, err := dedupPlaylist(ctx, yt, playlist); err != nil {
+		fatal(err)
+	}
+}
+
+// dedupPlaylist removes the duplicate videos from the playlist, returning the
+// outcome.
+func dedupPlaylist(ctx context.Context, yt *youtube.Service, playlist string) (string, error) {
 	type strTuple [2]string
 	itemID, videoID := 0, 1
 
-	if playlist == "" {
-		log.Fatal("You must specify a playlist with `-playlist` argument.")
-	}
-	req := yt.PlaylistItems.List([]string{"id,contentDetails"}).PlaylistId(playlist)
+	req := yt.PlaylistItems.List([]string{"id,contentDetails"}).PlaylistId(playlist).
+		Fields(ogle.ListFields("id", "contentDetails/videoId"))
 
 	videos := make([]strTuple, 0)
 	toRemove := make([]strTuple, 0, len(videos))
 	uniqueVids := make(map[string]strTuple, len(videos))
 
-	err := req.Pages(ctx, func(resp *youtube.PlaylistItemListResponse) error {
-		for _, item := range resp.Items {
-			v := strTuple{item.Id, item.ContentDetails.VideoId}
-			videos = append(videos, v)
-			if _, isDup := uniqueVids[v[videoID]]; isDup {
-				log.Printf("Duplicate video found with videoId=%v; itemId=%v", v[videoID], v[itemID])
-				toRemove = append(toRemove, v)
-				continue
-			}
-			uniqueVids[v[videoID]] = v
+	_, err := ogle.Paginate[*youtube.PlaylistItemListResponse](ctx, req, playlistItemItems, ogle.PageOptions{PageSize: pageSize}, func(item *youtube.PlaylistItem) error {
+		v := strTuple{item.Id, item.ContentDetails.VideoId}
+		videos = append(videos, v)
+		if _, isDup := uniqueVids[v[videoID]]; isDup {
+			log.Printf("Duplicate video found with videoId=%v; itemId=%v", v[videoID], v[itemID])
+			toRemove = append(toRemove, v)
+			return nil
 		}
+		uniqueVids[v[videoID]] = v
 		return nil
 	})
 	if err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 
-	if len(uniqueVids) != len(videos) {
-		log.Printf("Removing duplicates from playlistId=%v, will keep %d videos (down from %d)",
-			playlist, len(uniqueVids), len(videos))
+	if len(uniqueVids) == len(videos) {
+		log.Println("Playlist has no duplicate videos", len(uniqueVids), len(videos))
+		return "unchanged", nil
+	}
+	if dryRun {
+		stdoutMu.Lock()
 		for _, v := range toRemove {
-			id := v[itemID]
-			log.Printf("> Will remove playlistItem %s", id)
-			err := yt.PlaylistItems.Delete(id).Do()
-			if err != nil {
-				log.Fatal(err)
-			}
-			log.Printf("< Removed %v", id)
+			fmt.Fprintf(previewOutput(), "delete playlistItem %s (videoId=%s)\n", v[itemID], v[videoID])
 		}
-		return
+		stdoutMu.Unlock()
+		log.Printf("Dry run: %d duplicates were not removed from playlistId=%v", len(toRemove), playlist)
+		return "dry-run", nil
 	}
+	confirm(fmt.Sprintf("Remove %d duplicate videos from playlist %s?", len(toRemove), playlist))
+	log.Printf("Removing duplicates from playlistId=%v, will keep %d videos (down from %d)",
+		playlist, len(uniqueVids), len(videos))
+	_, err = ogle.Parallel(ctx, parallelism, toRemove, func(ctx context.Context, v strTuple) (struct{}, error) {
+		id := v[itemID]
+		log.Printf("> Will remove playlistItem %s", id)
+		if err := yt.PlaylistItems.Delete(id).Context(ctx).Do(); err != nil {
+			return struct{}{}, err
+		}
+		log.Printf("< Removed %v", id)
+		return struct{}{}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed %d", len(toRemove)), nil
+}
 
-	log.Println("Playlist has no duplicate videos", len(uniqueVids), len(videos))
+func channelItems(r *youtube.ChannelListResponse) []*youtube.Channel                { return r.Items }
+func subscriptionItems(r *youtube.SubscriptionListResponse) []*youtube.Subscription { return r.Items }
+func playlistItems(r *youtube.PlaylistListResponse) []*youtube.Playlist             { return r.Items }
+func playlistItemItems(r *youtube.PlaylistItemListResponse) []*youtube.PlaylistItem { return r.Items }
+func liveBroadcastItems(r *youtube.LiveBroadcastListResponse) []*youtube.LiveBroadcast {
+	return r.Items
 }
 
 type byPubDate []*youtube.LiveBroadcast
@@ -284,138 +569,458 @@ func (b byPubDate) Swap(i, j int)      { b[j], b[i] = b[i], b[j] }
 
 func listLives(yt *youtube.Service) {
 	count := 0
+	cols := selectColumns(liveBroadcastColumns, count)
+	defer closeOutput()
 
-	lives := make([]*youtube.LiveBroadcast, 0)
 	req := yt.LiveBroadcasts.List([]string{"id,snippet,contentDetails,status"}).BroadcastStatus("all")
-	err := req.Pages(ctx, func(resp *youtube.LiveBroadcastListResponse) error {
-		for _, item := range resp.Items {
-			lives = append(lives, item)
-		}
-		return nil
-	})
+	// Lives are always sorted by their publication date.
+	if mask := fieldsMask(cols, "snippet/publishedAt"); mask != "" {
+		req.Fields(mask)
+	}
+	lives, _, err := ogle.Collect[*youtube.LiveBroadcastListResponse](ctx, req, liveBroadcastItems, pageOptions())
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 	sort.Sort(byPubDate(lives))
 
-	w.Println("#", "PUBLISHED_AT", "ID", "TITLE", "STATUS", "URL")
-	defer w.Flush()
+	seen := make([]ogle.CompletionItem, 0, len(lives))
 	for _, item := range lives {
 		count++
-		url := "https://studio.youtube.com/video/" + item.Id + "/livestreaming"
-		w.Println(count, item.Snippet.PublishedAt, item.Id, item.Snippet.Title,
-			item.Status.LifeCycleStatus, url)
+		w.Row(item, cols.Values(count, item)...)
+		if item.Snippet != nil && item.Snippet.Title != "" {
+			seen = append(seen, ogle.CompletionItem{Value: item.Id, Description: item.Snippet.Title})
+		}
+	}
+	rememberCompletions("broadcasts", seen)
+}
+
+// videoEdits are the changes requested to a video or broadcast. Empty values
+// are left unchanged.
+type videoEdits struct {
+	Title       string
+	Description string
+	Category    string
+	Tags        string
+
+	Privacy             string
+	PublishAt           string
+	License             string
+	Embeddable          optionalBool
+	PublicStatsViewable optionalBool
+	MadeForKids         optionalBool
+
+	DefaultLanguage      string
+	DefaultAudioLanguage string
+
+	RecordingDate string
+	Location      string
+}
+
+// flagEdits returns the changes requested with command line flags.
+func flagEdits() videoEdits {
+	return videoEdits{
+		Title:                videoTitle,
+		Description:          videoDescription,
+		Category:             videoCategory,
+		Tags:                 videoTags,
+		Privacy:              videoPrivacy,
+		PublishAt:            videoPublishAt,
+		License:              videoLicense,
+		Embeddable:           videoEmbeddable,
+		PublicStatsViewable:  videoPublicStats,
+		MadeForKids:          videoMadeForKids,
+		DefaultLanguage:      videoLanguage,
+		DefaultAudioLanguage: videoAudioLanguage,
+		RecordingDate:        videoRecordingDate,
+		Location:             videoLocation,
+	}
+}
+
+// with overrides the edits with the values of a JSON input record.
+func (e videoEdits) with(in ogle.Input) videoEdits {
+	if v, ok := in.String("title"); ok {
+		e.Title = v
+	}
+	if v, ok := in.String("description", "desc"); ok {
+		e.Description = v
+	}
+	if v, ok := in.String("category", "categoryId"); ok {
+		e.Category = v
+	}
+	if v, ok := in.Strings("tags"); ok {
+		e.Tags = strings.Join(v, ",")
+	}
+	strs := map[*string][]string{
+		&e.Privacy:              {"privacy", "privacyStatus"},
+		&e.PublishAt:            {"publishAt"},
+		&e.License:              {"license"},
+		&e.DefaultLanguage:      {"defaultLanguage", "language"},
+		&e.DefaultAudioLanguage: {"defaultAudioLanguage", "audioLanguage"},
+		&e.RecordingDate:        {"recordingDate", "recorded"},
+		&e.Location:             {"location"},
+	}
+	for field, names := range strs {
+		if v, ok := in.String(names...); ok {
+			*field = v
+		}
+	}
+	bools := map[*optionalBool][]string{
+		&e.Embeddable:          {"embeddable"},
+		&e.PublicStatsViewable: {"publicStatsViewable", "publicStats"},
+		&e.MadeForKids:         {"madeForKids", "selfDeclaredMadeForKids"},
+	}
+	for field, names := range bools {
+		if v, ok := in.Bool(names...); ok {
+			*field = optionalBool{set: true, value: v}
+		}
+	}
+	return e
+}
+
+// validate checks the new title, description and tags before any request is
+// made. Incremental tag edits are checked again once applied.
+func (e videoEdits) validate() error {
+	tags, err := ogle.ApplyTagEdits(nil, e.Tags)
+	if err != nil {
+		return ogle.UsageError("%v", err)
 	}
+	return ogle.VideoMetadata{Title: e.Title, Description: e.Description, Tags: tags}.Validate()
+}
+
+// validateBroadcast checks the new title and description, the only fields
+// sent when updating a broadcast.
+func (e videoEdits) validateBroadcast() error {
+	return ogle.VideoMetadata{Title: e.Title, Description: e.Description}.Validate()
+}
+
+// parts returns the video parts changed by the edits.
+func (e videoEdits) parts() []string {
+	parts := []string{"id"}
+	if e.Title != "" || e.Description != "" || e.Category != "" || e.Tags != "" ||
+		e.DefaultLanguage != "" || e.DefaultAudioLanguage != "" {
+		parts = append(parts, "snippet")
+	}
+	if e.Privacy != "" || e.PublishAt != "" || e.License != "" ||
+		e.Embeddable.set || e.PublicStatsViewable.set || e.MadeForKids.set {
+		parts = append(parts, "status")
+	}
+	if e.RecordingDate != "" || e.Location != "" {
+		parts = append(parts, "recordingDetails")
+	}
+	return parts
 }
 
 func updateLive(yt *youtube.Service) {
+	if video == stdinArg {
+		runBatch("video", ogle.ParseVideoID, func(ctx context.Context, in ogle.Input, id string) (string, error) {
+			return updateBroadcast(ctx, yt, id, flagEdits().with(in))
+		})
+		return
+	}
 	if video == "" {
-		log.Fatal("No video_id provided. Use the -video flag to define what live we need to update.")
+		fatal(ogle.UsageError("No video_id provided. Use the -video flag to define what live we need to update."))
+	}
+	if _, err := updateBroadcast(ctx, yt, video, flagEdits()); err != nil {
+		fatal(err)
 	}
+}
 
+// updateBroadcast applies the title and description edits to the broadcast,
+// returning the outcome.
+func updateBroadcast(ctx context.Context, yt *youtube.Service, id string, e videoEdits) (string, error) {
+	if err := e.validateBroadcast(); err != nil {
+		return "", err
+	}
 	parts := []string{"id,snippet"}
-	req := yt.LiveBroadcasts.List(parts).Id(video)
-	resp, err := req.Do()
+	req := yt.LiveBroadcasts.List(parts).Id(id)
+	resp, err := req.Context(ctx).Do()
 	if err != nil {
-		log.Fatalf("Error loading live stream details: %v", err)
+		return "", fmt.Errorf("error loading live stream details: %w", err)
+	}
+	if len(resp.Items) == 0 {
+		return "", ogle.NewFailure("videoNotFound", fmt.Errorf("no live matched the provided id '%s'", id))
 	}
 	videoPayload := resp.Items[0]
 
-	msg := "Updated Fields: "
-	if videoTitle != "" {
-		videoPayload.Snippet.Title = videoTitle
-		msg = msg + "title "
+	changes := ogle.Changes{}
+	if e.Title != "" {
+		changes.Add("title", videoPayload.Snippet.Title, e.Title)
+		videoPayload.Snippet.Title = e.Title
 	}
-	if videoDescription != "" {
-		videoPayload.Snippet.Description = videoDescription
-		msg = msg + "description "
+	if e.Description != "" {
+		changes.Add("description", videoPayload.Snippet.Description, e.Description)
+		videoPayload.Snippet.Description = e.Description
 	}
-	log.Printf("Updating live (id=%v). %v", video, msg)
-	_, err = yt.LiveBroadcasts.Update(parts, videoPayload).Do()
+	if skip := preview("live", id, changes); skip != "" {
+		return skip, nil
+	}
+	log.Printf("Updating live (id=%v). Updated fields: %v", id, strings.Join(changes.Fields(), ", "))
+	_, err = yt.LiveBroadcasts.Update(parts, videoPayload).Context(ctx).Do()
 	if err != nil {
-		log.Fatalf("Error updating live: %v", err)
+		return "", fmt.Errorf("error updating live: %w", err)
 	}
 	log.Printf("Live updated")
+	return "updated", nil
 }
 
 func videoUpdate(yt *youtube.Service) {
+	if videoEdit {
+		if video == "" || video == stdinArg {
+			fatal(ogle.UsageError("-edit requires a single video set with -video"))
+		}
+		if len(flagEdits().parts()) > 1 {
+			fatal(ogle.UsageError("-edit cannot be combined with other changes"))
+		}
+		if _, err := editVideo(ctx, yt, video); err != nil {
+			fatal(err)
+		}
+		return
+	}
+	if video == stdinArg {
+		runBatch("video", ogle.ParseVideoID, func(ctx context.Context, in ogle.Input, id string) (string, error) {
+			return updateVideo(ctx, yt, id, flagEdits().with(in))
+		})
+		return
+	}
 	if video == "" {
-		log.Fatal("No video_id provided. Use the -video flag to define what video we need to update.")
+		fatal(ogle.UsageError("No video_id provided. Use the -video flag to define what video we need to update."))
 	}
+	if _, err := updateVideo(ctx, yt, video, flagEdits()); err != nil {
+		fatal(err)
+	}
+}
 
-	var videoPayload *youtube.Video
-	parts := []string{"id,snippet"}
-	req := yt.Videos.List(parts).Id(video)
-	err := req.Pages(ctx, func(resp *youtube.VideoListResponse) error {
-		if len(resp.Items) == 0 {
-			return fmt.Errorf("No vídeos matched the provided id '%s'", video)
-		}
-		videoPayload = resp.Items[0]
-		return nil
-	})
+// updateVideo applies the edits to the video, returning the outcome. Only the
+// parts with changes are requested and updated, so other fields are kept.
+func updateVideo(ctx context.Context, yt *youtube.Service, id string, e videoEdits) (string, error) {
+	if err := e.validate(); err != nil {
+		return "", err
+	}
+	var err error
+	if e.Category, err = categoryID(ctx, yt, e.Category); err != nil {
+		return "", err
+	}
+	parts := e.parts()
+	if len(parts) == 1 {
+		return preview("video", id, nil), nil
+	}
+	resp, err := yt.Videos.List(parts).Id(id).Context(ctx).Do()
 	if err != nil {
-		log.Fatalf("Error: %v", err)
+		return "", err
 	}
-
-	msg := "Updated Fields: "
-	if videoTitle != "" {
-		videoPayload.Snippet.Title = videoTitle
-		msg = msg + fmt.Sprintf("[title: %v]", videoTitle)
+	if len(resp.Items) == 0 {
+		return "", ogle.NewFailure("videoNotFound", fmt.Errorf("No vídeos matched the provided id '%s'", id))
 	}
-	if videoDescription != "" {
-		videoPayload.Snippet.Description = videoDescription
-		msg = msg + fmt.Sprintf("[description: %v]", videoDescription)
+	videoPayload := resp.Items[0]
+
+	changes := ogle.Changes{}
+	if videoPayload.Snippet != nil {
+		if err := e.applySnippet(videoPayload.Snippet, &changes); err != nil {
+			return "", err
+		}
 	}
-	if videoCategory != "" {
-		videoPayload.Snippet.CategoryId = videoCategory
-		msg = msg + fmt.Sprintf("[categoryId: %v]", videoCategory)
+	if videoPayload.Status != nil {
+		if err := e.applyStatus(videoPayload.Status, &changes); err != nil {
+			return "", err
+		}
 	}
-	if videoTags != "" {
-		cleanedTags := []string{}
-		for _, tag := range strings.Split(videoTags, ",") {
-			cleanedTags = append(cleanedTags, strings.TrimSpace(tag))
+	if e.RecordingDate != "" || e.Location != "" {
+		if videoPayload.RecordingDetails == nil {
+			videoPayload.RecordingDetails = &youtube.VideoRecordingDetails{}
+		}
+		if err := e.applyRecording(videoPayload.RecordingDetails, &changes); err != nil {
+			return "", err
 		}
-		videoPayload.Snippet.Tags = cleanedTags
-		msg = msg + fmt.Sprintf("[tags: %v]", strings.Join(cleanedTags, ","))
+	}
+	if skip := preview("video", id, changes); skip != "" {
+		return skip, nil
 	}
 
-	log.Printf("Updating video (id=%s). %s", video, msg)
-	_, err = yt.Videos.Update(parts, videoPayload).Do()
+	log.Printf("Updating video (id=%s). Updated fields: %s", id, strings.Join(changes.Fields(), ", "))
+	_, err = yt.Videos.Update(parts, videoPayload).Context(ctx).Do()
 	if err != nil {
-		log.Fatalf("Error updating video: %v", err)
+		return "", fmt.Errorf("error updating video: %w", err)
 	}
 	log.Println("Vídeo updated")
+	return "updated", nil
 }
 
-func logout() {
-	if err := ogle.RemoveTokenFromCache("youtube"); err != nil {
-		log.Fatalf("Unable to remove authentication token: %v", err)
+func (e videoEdits) applySnippet(s *youtube.VideoSnippet, changes *ogle.Changes) error {
+	if e.Title != "" {
+		changes.Add("title", s.Title, e.Title)
+		s.Title = e.Title
 	}
-	log.Println("Authentication token removed.")
+	if e.Description != "" {
+		changes.Add("description", s.Description, e.Description)
+		s.Description = e.Description
+	}
+	if e.Category != "" {
+		changes.Add("categoryId", s.CategoryId, e.Category)
+		s.CategoryId = e.Category
+	}
+	if e.Tags != "" {
+		tags, err := ogle.ApplyTagEdits(s.Tags, e.Tags)
+		if err != nil {
+			return ogle.UsageError("%v", err)
+		}
+		if err := (ogle.VideoMetadata{Tags: tags}).Validate(); err != nil {
+			return err
+		}
+		changes.AddList("tags", s.Tags, tags)
+		s.Tags = tags
+	}
+	if e.DefaultLanguage != "" {
+		changes.Add("defaultLanguage", s.DefaultLanguage, e.DefaultLanguage)
+		s.DefaultLanguage = e.DefaultLanguage
+	}
+	if e.DefaultAudioLanguage != "" {
+		changes.Add("defaultAudioLanguage", s.DefaultAudioLanguage, e.DefaultAudioLanguage)
+		s.DefaultAudioLanguage = e.DefaultAudioLanguage
+	}
+	return nil
 }
 
-// From: https://go.dev/play/p/SWY4Lu5Ano5
-func substr(s string, from, length int) string {
-	//create array like string view
-	wb := []string{}
-	wb = strings.Split(s, "")
+// videoPrivacies are the accepted privacy statuses.
+var videoPrivacies = []string{"private", "unlisted", "public"}
 
-	//miss nil pointer error
-	to := from + length
+// videoLicenses are the accepted video licenses.
+var videoLicenses = []string{"youtube", "creativeCommon"}
 
-	if to > len(wb) {
-		to = len(wb)
+func oneOf(value string, valid []string) bool {
+	for _, v := range valid {
+		if v == value {
+			return true
+		}
 	}
+	return false
+}
 
-	if from > len(wb) {
-		from = len(wb)
+func (e videoEdits) applyStatus(s *youtube.VideoStatus, changes *ogle.Changes) error {
+	// The whole status is replaced, and false values are omitted from the
+	// request unless forced, which would reset them to their defaults.
+	s.ForceSendFields = append(s.ForceSendFields, "Embeddable", "PublicStatsViewable", "SelfDeclaredMadeForKids")
+
+	if e.Privacy != "" {
+		if !oneOf(e.Privacy, videoPrivacies) {
+			return ogle.UsageError("invalid privacy %q, use one of: %s", e.Privacy, strings.Join(videoPrivacies, ", "))
+		}
+		changes.Add("privacyStatus", s.PrivacyStatus, e.Privacy)
+		s.PrivacyStatus = e.Privacy
+	}
+	switch e.PublishAt {
+	case "":
+	case "none":
+		changes.Add("publishAt", s.PublishAt, "")
+		s.PublishAt = ""
+		s.NullFields = append(s.NullFields, "PublishAt")
+	default:
+		publishAt, err := parsePublishAt(e.PublishAt)
+		if err != nil {
+			return err
+		}
+		if s.PrivacyStatus != "private" {
+			if e.Privacy != "" {
+				return ogle.UsageError("scheduled videos must be private, not %s", e.Privacy)
+			}
+			// Videos are only published at the scheduled time if private.
+			changes.Add("privacyStatus", s.PrivacyStatus, "private")
+			s.PrivacyStatus = "private"
+		}
+		changes.Add("publishAt", s.PublishAt, publishAt)
+		s.PublishAt = publishAt
+	}
+	if e.License != "" {
+		if !oneOf(e.License, videoLicenses) {
+			return ogle.UsageError("invalid license %q, use one of: %s", e.License, strings.Join(videoLicenses, ", "))
+		}
+		changes.Add("license", s.License, e.License)
+		s.License = e.License
 	}
+	if e.Embeddable.set {
+		changes.Add("embeddable", strconv.FormatBool(s.Embeddable), strconv.FormatBool(e.Embeddable.value))
+		s.Embeddable = e.Embeddable.value
+	}
+	if e.PublicStatsViewable.set {
+		changes.Add("publicStatsViewable", strconv.FormatBool(s.PublicStatsViewable), strconv.FormatBool(e.PublicStatsViewable.value))
+		s.PublicStatsViewable = e.PublicStatsViewable.value
+	}
+	if e.MadeForKids.set {
+		changes.Add("selfDeclaredMadeForKids", strconv.FormatBool(s.SelfDeclaredMadeForKids), strconv.FormatBool(e.MadeForKids.value))
+		s.SelfDeclaredMadeForKids = e.MadeForKids.value
+	}
+	return nil
+}
+
+// parsePublishAt parses the scheduled publish time of a video, returning it in
+// UTC as required by the API.
+func parsePublishAt(s string) (string, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return "", ogle.UsageError("invalid publish time %q, use RFC 3339 like 2024-05-01T18:00:00Z", s)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+func (e videoEdits) applyRecording(r *youtube.VideoRecordingDetails, changes *ogle.Changes) error {
+	if e.RecordingDate != "" {
+		t, err := time.Parse("2006-01-02", e.RecordingDate)
+		if err != nil {
+			if t, err = time.Parse(time.RFC3339, e.RecordingDate); err != nil {
+				return ogle.UsageError("invalid recording date %q, use 2006-01-02 or RFC 3339", e.RecordingDate)
+			}
+		}
+		date := t.UTC().Format(time.RFC3339)
+		changes.Add("recordingDate", r.RecordingDate, date)
+		r.RecordingDate = date
+	}
+	if e.Location != "" {
+		loc, err := parseGeoPoint(e.Location)
+		if err != nil {
+			return err
+		}
+		changes.Add("location", formatGeoPoint(r.Location), formatGeoPoint(loc))
+		r.Location = loc
+	}
+	return nil
+}
+
+// parseGeoPoint parses a "latitude,longitude" pair.
+func parseGeoPoint(s string) (*youtube.GeoPoint, error) {
+	lat, lng, ok := strings.Cut(s, ",")
+	p := &youtube.GeoPoint{ForceSendFields: []string{"Latitude", "Longitude"}}
+	var err error
+	if ok {
+		if p.Latitude, err = strconv.ParseFloat(strings.TrimSpace(lat), 64); err == nil {
+			p.Longitude, err = strconv.ParseFloat(strings.TrimSpace(lng), 64)
+		}
+	}
+	// Written as ranges, so NaN values are rejected as well.
+	inRange := p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+	if !ok || err != nil || !inRange {
+		return nil, ogle.UsageError("invalid location %q, use latitude,longitude like -23.55,-46.63", s)
+	}
+	return p, nil
+}
+
+func formatGeoPoint(p *youtube.GeoPoint) string {
+	if p == nil {
+		return ""
+	}
+	return fmt.Sprintf("%g,%g", p.Latitude, p.Longitude)
+}
+
+func logout() {
+	if err := ogle.RemoveTokenFromCache("youtube"); err != nil {
+		fatal(fmt.Errorf("unable to remove authentication token: %w", err))
+	}
+	log.Println("Authentication token removed.")
+}
 
-	out := strings.Join(wb[from:to], "")
-	if s == out {
-		return s
+func clearCache() {
+	if err := ogle.ClearCache("youtube"); err != nil {
+		fatal(fmt.Errorf("unable to clear cache: %w", err))
 	}
-	return out + "..."
+	log.Println("Cache cleared.")
 }
 
 func dumpjson(v interface{}) {
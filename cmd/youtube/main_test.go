@@ -0,0 +1,262 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ronoaldo/ogle"
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestApplyStatus(t *testing.T) {
+	on := optionalBool{set: true, value: true}
+	off := optionalBool{set: true, value: false}
+	tests := []struct {
+		name    string
+		edits   videoEdits
+		status  youtube.VideoStatus
+		want    youtube.VideoStatus
+		changed []string
+		err     string
+	}{
+		{
+			name:    "privacy",
+			edits:   videoEdits{Privacy: "unlisted"},
+			status:  youtube.VideoStatus{PrivacyStatus: "private"},
+			want:    youtube.VideoStatus{PrivacyStatus: "unlisted"},
+			changed: []string{"privacyStatus"},
+		},
+		{
+			name:  "invalid privacy",
+			edits: videoEdits{Privacy: "hidden"},
+			err:   `invalid privacy "hidden"`,
+		},
+		{
+			name:    "schedule a private video",
+			edits:   videoEdits{PublishAt: "2024-05-01T15:00:00-03:00"},
+			status:  youtube.VideoStatus{PrivacyStatus: "private"},
+			want:    youtube.VideoStatus{PrivacyStatus: "private", PublishAt: "2024-05-01T18:00:00Z"},
+			changed: []string{"publishAt"},
+		},
+		{
+			name:    "schedule forces private",
+			edits:   videoEdits{PublishAt: "2024-05-01T18:00:00Z"},
+			status:  youtube.VideoStatus{PrivacyStatus: "public"},
+			want:    youtube.VideoStatus{PrivacyStatus: "private", PublishAt: "2024-05-01T18:00:00Z"},
+			changed: []string{"privacyStatus", "publishAt"},
+		},
+		{
+			name:    "schedule with explicit private",
+			edits:   videoEdits{Privacy: "private", PublishAt: "2024-05-01T18:00:00Z"},
+			status:  youtube.VideoStatus{PrivacyStatus: "unlisted"},
+			want:    youtube.VideoStatus{PrivacyStatus: "private", PublishAt: "2024-05-01T18:00:00Z"},
+			changed: []string{"privacyStatus", "publishAt"},
+		},
+		{
+			name:   "schedule conflicts with public",
+			edits:  videoEdits{Privacy: "public", PublishAt: "2024-05-01T18:00:00Z"},
+			status: youtube.VideoStatus{PrivacyStatus: "private"},
+			err:    "scheduled videos must be private, not public",
+		},
+		{
+			name:  "invalid schedule",
+			edits: videoEdits{PublishAt: "2024-05-01 18:00"},
+			err:   `invalid publish time "2024-05-01 18:00"`,
+		},
+		{
+			name:    "cancel the schedule",
+			edits:   videoEdits{PublishAt: "none"},
+			status:  youtube.VideoStatus{PrivacyStatus: "private", PublishAt: "2024-05-01T18:00:00Z"},
+			want:    youtube.VideoStatus{PrivacyStatus: "private", NullFields: []string{"PublishAt"}},
+			changed: []string{"publishAt"},
+		},
+		{
+			name:    "license",
+			edits:   videoEdits{License: "creativeCommon"},
+			status:  youtube.VideoStatus{License: "youtube"},
+			want:    youtube.VideoStatus{License: "creativeCommon"},
+			changed: []string{"license"},
+		},
+		{
+			name:  "invalid license",
+			edits: videoEdits{License: "cc-by"},
+			err:   `invalid license "cc-by"`,
+		},
+		{
+			name:    "booleans",
+			edits:   videoEdits{Embeddable: off, PublicStatsViewable: on, MadeForKids: on},
+			status:  youtube.VideoStatus{Embeddable: true},
+			want:    youtube.VideoStatus{PublicStatsViewable: true, SelfDeclaredMadeForKids: true},
+			changed: []string{"embeddable", "publicStatsViewable", "selfDeclaredMadeForKids"},
+		},
+		{
+			name:   "unset booleans are kept",
+			edits:  videoEdits{Privacy: "public"},
+			status: youtube.VideoStatus{PrivacyStatus: "public", Embeddable: true, SelfDeclaredMadeForKids: true},
+			want:   youtube.VideoStatus{PrivacyStatus: "public", Embeddable: true, SelfDeclaredMadeForKids: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.status
+			var changes ogle.Changes
+			err := tt.edits.applyStatus(&s, &changes)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("applyStatus() = %v, want error containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// False values must always be sent, or the API resets them.
+			want := tt.want
+			want.ForceSendFields = []string{"Embeddable", "PublicStatsViewable", "SelfDeclaredMadeForKids"}
+			if !reflect.DeepEqual(s, want) {
+				t.Errorf("status\n got %+v\nwant %+v", s, want)
+			}
+			if got := changes.Fields(); !reflect.DeepEqual(got, append([]string{}, tt.changed...)) {
+				t.Errorf("changed fields %v, want %v", got, tt.changed)
+			}
+		})
+	}
+}
+
+func TestApplyRecording(t *testing.T) {
+	tests := []struct {
+		edits videoEdits
+		want  youtube.VideoRecordingDetails
+		err   string
+	}{
+		{
+			edits: videoEdits{RecordingDate: "2024-05-01"},
+			want:  youtube.VideoRecordingDetails{RecordingDate: "2024-05-01T00:00:00Z"},
+		},
+		{
+			edits: videoEdits{RecordingDate: "2024-05-01T10:00:00+02:00"},
+			want:  youtube.VideoRecordingDetails{RecordingDate: "2024-05-01T08:00:00Z"},
+		},
+		{
+			edits: videoEdits{RecordingDate: "01/05/2024"},
+			err:   `invalid recording date "01/05/2024"`,
+		},
+		{
+			edits: videoEdits{Location: "-23.55, -46.63"},
+			want: youtube.VideoRecordingDetails{Location: &youtube.GeoPoint{
+				Latitude: -23.55, Longitude: -46.63, ForceSendFields: []string{"Latitude", "Longitude"},
+			}},
+		},
+		{
+			edits: videoEdits{Location: "0,0"},
+			want: youtube.VideoRecordingDetails{Location: &youtube.GeoPoint{
+				ForceSendFields: []string{"Latitude", "Longitude"},
+			}},
+		},
+		{
+			edits: videoEdits{Location: "Sao Paulo"},
+			err:   `invalid location "Sao Paulo"`,
+		},
+	}
+	for _, tt := range tests {
+		var r youtube.VideoRecordingDetails
+		var changes ogle.Changes
+		err := tt.edits.applyRecording(&r, &changes)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("applyRecording(%+v) = %v, want error containing %q", tt.edits, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("applyRecording(%+v): %v", tt.edits, err)
+			continue
+		}
+		if !reflect.DeepEqual(r, tt.want) {
+			t.Errorf("applyRecording(%+v)\n got %+v\nwant %+v", tt.edits, r, tt.want)
+		}
+	}
+}
+
+func TestParseGeoPoint(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lng float64
+		valid    bool
+	}{
+		{"-23.55,-46.63", -23.55, -46.63, true},
+		{" 51.5 , -0.12 ", 51.5, -0.12, true},
+		{"90,180", 90, 180, true},
+		{"-90,-180", -90, -180, true},
+		{"0,0", 0, 0, true},
+		{"90.1,0", 0, 0, false},
+		{"-91,0", 0, 0, false},
+		{"0,180.5", 0, 0, false},
+		{"0,-181", 0, 0, false},
+		{"-23.55", 0, 0, false},
+		{"-23.55;-46.63", 0, 0, false},
+		{"a,b", 0, 0, false},
+		{"1,2,3", 0, 0, false},
+		{",", 0, 0, false},
+		{"", 0, 0, false},
+		{"NaN,0", 0, 0, false},
+	}
+	for _, tt := range tests {
+		p, err := parseGeoPoint(tt.in)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("parseGeoPoint(%q) = %+v, want an error", tt.in, p)
+			}
+			continue
+		}
+		if err != nil || p.Latitude != tt.lat || p.Longitude != tt.lng {
+			t.Errorf("parseGeoPoint(%q) = (%+v, %v), want %g,%g", tt.in, p, err, tt.lat, tt.lng)
+		}
+	}
+	if got := formatGeoPoint(&youtube.GeoPoint{Latitude: -23.55, Longitude: -46.63}); got != "-23.55,-46.63" {
+		t.Errorf("formatGeoPoint() = %q", got)
+	}
+}
+
+func TestParsePublishAt(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"2024-05-01T18:00:00Z", "2024-05-01T18:00:00Z"},
+		{"2024-05-01T15:00:00-03:00", "2024-05-01T18:00:00Z"},
+		{"2024-05-01T18:00:00.5Z", "2024-05-01T18:00:00Z"},
+		{"2024-05-01", ""},
+		{"tomorrow", ""},
+	}
+	for _, tt := range tests {
+		got, err := parsePublishAt(tt.in)
+		if got != tt.want || (err == nil) != (tt.want != "") {
+			t.Errorf("parsePublishAt(%q) = (%q, %v), want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestValidateEdits(t *testing.T) {
+	tests := []struct {
+		name      string
+		edits     videoEdits
+		video     bool
+		broadcast bool
+	}{
+		{"valid", videoEdits{Title: "Live coding #42", Tags: "+go,-java"}, true, true},
+		{"invalid title", videoEdits{Title: "a <b>"}, false, false},
+		{"long description", videoEdits{Description: strings.Repeat("x", 5001)}, false, false},
+		{"mixed tag edits", videoEdits{Title: "Live", Tags: "+go,java"}, false, true},
+		{"long tags", videoEdits{Tags: strings.Repeat("x", 501)}, false, true},
+		{"tags with angle brackets", videoEdits{Tags: "<go>"}, false, true},
+	}
+	for _, tt := range tests {
+		if err := tt.edits.validate(); (err == nil) != tt.video {
+			t.Errorf("%s: validate() = %v, want valid %v", tt.name, err, tt.video)
+		}
+		if err := tt.edits.validateBroadcast(); (err == nil) != tt.broadcast {
+			t.Errorf("%s: validateBroadcast() = %v, want valid %v", tt.name, err, tt.broadcast)
+		}
+	}
+}
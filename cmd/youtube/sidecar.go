@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ronoaldo/ogle"
+	"google.golang.org/api/youtube/v3"
+)
+
+// scalar is a string that can also be decoded from a number or boolean, so a
+// sidecar can have "category: 22" without quotes.
+type scalar string
+
+func (s *scalar) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(b, []byte(`"`)) {
+		var str string
+		if err := json.Unmarshal(b, &str); err != nil {
+			return err
+		}
+		*s = scalar(str)
+		return nil
+	}
+	if string(b) != "null" {
+		*s = scalar(b)
+	}
+	return nil
+}
+
+// localization is the title and description of a video in one language.
+type localization struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// sidecar is the metadata of a video file to upload, read from a JSON or YAML
+// file next to it, like video.mp4.yaml. The result of the upload is recorded
+// in the same file.
+type sidecar struct {
+	Title           string                  `json:"title,omitempty"`
+	Description     string                  `json:"description,omitempty"`
+	Tags            []string                `json:"tags,omitempty"`
+	Category        scalar                  `json:"category,omitempty"`
+	Privacy         string                  `json:"privacy,omitempty"`
+	Thumbnail       string                  `json:"thumbnail,omitempty"`
+	Playlists       []string                `json:"playlists,omitempty"`
+	Schedule        string                  `json:"schedule,omitempty"`
+	DefaultLanguage string                  `json:"defaultLanguage,omitempty"`
+	Localizations   map[string]localization `json:"localizations,omitempty"`
+
+	// Upload results.
+	VideoID    string `json:"videoId,omitempty"`
+	URL        string `json:"url,omitempty"`
+	UploadedAt string `json:"uploadedAt,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	// file is the name of the sidecar file, empty if it does not exist.
+	file string
+}
+
+// loadSidecar reads the sidecar of the video file, if any, and applies the
+// upload flags, which override the values in the file.
+func loadSidecar(file string) (*sidecar, error) {
+	s := &sidecar{file: ogle.FindSidecar(file)}
+	if s.file != "" {
+		if err := ogle.LoadSidecar(s.file, s); err != nil {
+			return nil, err
+		}
+	}
+	e := flagEdits()
+	if e.Title != "" {
+		s.Title = e.Title
+	}
+	if e.Description != "" {
+		s.Description = e.Description
+	}
+	if e.Category != "" {
+		s.Category = scalar(e.Category)
+	}
+	if e.Tags != "" {
+		tags, err := ogle.ApplyTagEdits(s.Tags, e.Tags)
+		if err != nil {
+			return nil, ogle.UsageError("%v", err)
+		}
+		s.Tags = tags
+	}
+	if setFlags["privacy"] || s.Privacy == "" {
+		s.Privacy = videoPrivacy
+	}
+	if playlist != "" {
+		s.Playlists = append(s.Playlists, playlist)
+	}
+	return s, nil
+}
+
+// video returns the resource to upload, in the given category ID. The title
+// defaults to the file name, without its extension. Scheduled videos are
+// uploaded as private and made public at the scheduled time.
+func (s *sidecar) video(file, category string) *youtube.Video {
+	title := s.Title
+	if title == "" {
+		base := filepath.Base(file)
+		title = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	v := &youtube.Video{
+		Snippet: &youtube.VideoSnippet{
+			Title:           title,
+			Description:     s.Description,
+			Tags:            s.Tags,
+			CategoryId:      category,
+			DefaultLanguage: s.DefaultLanguage,
+		},
+		Status: &youtube.VideoStatus{PrivacyStatus: s.Privacy},
+	}
+	if s.Schedule != "" {
+		v.Status.PrivacyStatus = "private"
+		v.Status.PublishAt = s.Schedule
+		// Already checked by validate; the API expects the time in UTC.
+		if publishAt, err := parsePublishAt(s.Schedule); err == nil {
+			v.Status.PublishAt = publishAt
+		}
+	}
+	if len(s.Localizations) > 0 {
+		v.Localizations = make(map[string]youtube.VideoLocalization, len(s.Localizations))
+		for lang, l := range s.Localizations {
+			v.Localizations[lang] = youtube.VideoLocalization{Title: l.Title, Description: l.Description}
+		}
+	}
+	return v
+}
+
+// validate checks the metadata of the video before it is uploaded.
+func (s *sidecar) validate(file string) error {
+	if s.Schedule != "" {
+		if _, err := parsePublishAt(s.Schedule); err != nil {
+			return ogle.UsageError("invalid schedule %q, use RFC 3339 like 2024-05-01T18:00:00Z", s.Schedule)
+		}
+	}
+	snippet := s.video(file, string(s.Category)).Snippet
+	return ogle.VideoMetadata{Title: snippet.Title, Description: snippet.Description, Tags: snippet.Tags}.Validate()
+}
+
+// thumbnailFile returns the thumbnail file name, relative to the directory of
+// the video file.
+func (s *sidecar) thumbnailFile(file string) string {
+	if s.Thumbnail == "" || filepath.IsAbs(s.Thumbnail) {
+		return s.Thumbnail
+	}
+	return filepath.Join(filepath.Dir(file), s.Thumbnail)
+}
+
+// record stores the upload result. The video may have been uploaded even if
+// a later step, like adding it to a playlist, failed.
+func (s *sidecar) record(v *youtube.Video, err error) {
+	if v != nil {
+		s.VideoID = v.Id
+		s.URL = "https://youtu.be/" + v.Id
+		s.UploadedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	s.Error = ""
+	if err != nil {
+		s.Error = ogle.Explain(err).Message
+	}
+}
+
+// save writes the sidecar, creating a YAML one next to the video file if it
+// did not exist.
+func (s *sidecar) save(file string) error {
+	if s.file == "" {
+		s.file = file + ".yaml"
+	}
+	return ogle.SaveSidecar(s.file, s)
+}
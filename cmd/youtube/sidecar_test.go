@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSidecarSchedule(t *testing.T) {
+	tests := []struct {
+		schedule  string
+		privacy   string
+		publishAt string
+		err       string
+	}{
+		{schedule: "", privacy: "public"},
+		{schedule: "2024-05-01T18:00:00Z", privacy: "private", publishAt: "2024-05-01T18:00:00Z"},
+		{schedule: "2024-05-01T15:00:00-03:00", privacy: "private", publishAt: "2024-05-01T18:00:00Z"},
+		{schedule: "2024-05-01 18:00", err: `invalid schedule "2024-05-01 18:00"`},
+		{schedule: "2024-13-01T18:00:00Z", err: "invalid schedule"},
+		{schedule: "tomorrow", err: "invalid schedule"},
+	}
+	for _, tt := range tests {
+		s := &sidecar{Title: "Episode 1", Privacy: "public", Schedule: tt.schedule}
+		err := s.validate("ep1.mp4")
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("validate() with schedule %q = %v, want error containing %q", tt.schedule, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("validate() with schedule %q: %v", tt.schedule, err)
+			continue
+		}
+		status := s.video("ep1.mp4", "22").Status
+		if status.PrivacyStatus != tt.privacy || status.PublishAt != tt.publishAt {
+			t.Errorf("schedule %q: got status (%s, %s), want (%s, %s)",
+				tt.schedule, status.PrivacyStatus, status.PublishAt, tt.privacy, tt.publishAt)
+		}
+	}
+}
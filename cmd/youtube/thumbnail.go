@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ronoaldo/ogle"
+	"golang.org/x/net/context"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Thumbnail command line options
+var (
+	thumbnailFit  bool
+	thumbnailSize = "best"
+	thumbnailDir  = "."
+	templateFile  string
+	templateVars  = make(templateVarsFlag)
+	renderOutput  string
+)
+
+// thumbnailSizes are the names of the thumbnails of a video, from the largest
+// to the smallest.
+var thumbnailSizes = []string{"maxres", "standard", "high", "medium", "default"}
+
+func thumbnailSetFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&thumbnailFit, "fit", false, "Crop, resize and re-encode the image if it is not a valid thumbnail.")
+}
+
+func thumbnailGetFlags(fs *flag.FlagSet) {
+	fs.StringVar(&thumbnailSize, "size", "best", "The `sizes` to download: best, all or a list of "+strings.Join(thumbnailSizes, ", ")+".")
+	fs.StringVar(&thumbnailDir, "dir", ".", "The `directory` where thumbnails are saved.")
+}
+
+func thumbnailRenderFlags(fs *flag.FlagSet) {
+	fs.StringVar(&video, "video", "", "The `video` to set the rendered thumbnail on: an ID or video URL.")
+	fs.StringVar(&templateFile, "template", "", "The thumbnail template `file`, in JSON or YAML.")
+	fs.Var(templateVars, "var", "Set a template variable, as `name=value`. Can be repeated.")
+	fs.StringVar(&renderOutput, "out", "", "The `file` to save the thumbnail to, as JPEG or PNG depending on its extension.")
+}
+
+// templateVarsFlag holds the variables given with -var.
+type templateVarsFlag map[string]string
+
+func (v templateVarsFlag) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (v templateVarsFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
+// renderThumbnail renders a thumbnail template, saving it to -out and setting
+// it on -video. The API is only used when -video is given.
+func renderThumbnail(*youtube.Service) {
+	if templateFile == "" {
+		fatal(ogle.UsageError("No template provided. Use the -template flag to define the thumbnail template."))
+	}
+	if video == stdinArg {
+		fatal(ogle.UsageError("-video - is not supported, render one thumbnail at a time"))
+	}
+	if renderOutput == "" && video == "" {
+		fatal(ogle.UsageError("Use -out to save the thumbnail or -video to set it on a video."))
+	}
+	format := "jpeg"
+	if strings.ToLower(filepath.Ext(renderOutput)) == ".png" {
+		format = "png"
+	}
+	t, err := ogle.LoadThumbnailTemplate(templateFile)
+	if err != nil {
+		fatal(err)
+	}
+	img, err := t.Render(templateVars)
+	if err != nil {
+		fatal(err)
+	}
+	data, err := ogle.EncodeThumbnail(img, format)
+	if err != nil {
+		fatal(err)
+	}
+	if renderOutput != "" {
+		tmp := renderOutput + ".tmp"
+		if err := os.WriteFile(tmp, data, 0644); err != nil {
+			fatal(err)
+		}
+		if err := os.Rename(tmp, renderOutput); err != nil {
+			fatal(err)
+		}
+		log.Printf("Thumbnail saved to %s (%s)", renderOutput, ogle.FormatBytes(uint64(len(data))))
+	}
+	if video == "" {
+		return
+	}
+	if dryRun {
+		log.Printf("Dry run: rendered thumbnail would be set on video %s", video)
+		return
+	}
+	yt := newService()
+	if err := setThumbnail(ctx, yt, video, data); err != nil {
+		fatal(err)
+	}
+	log.Printf("Thumbnail updated")
+}
+
+func setVideoThumbnail(yt *youtube.Service) {
+	if video == "" || video == stdinArg {
+		fatal(ogle.UsageError("No video_id provided. Use the -video flag to define what video we need to update."))
+	}
+	if len(commandArgs) != 1 {
+		fatal(ogle.UsageError("expected one image file"))
+	}
+	data, err := loadThumbnail(commandArgs[0])
+	if err != nil {
+		fatal(err)
+	}
+	if dryRun {
+		log.Printf("Dry run: thumbnail of video %s would be set from %s (%s)",
+			video, commandArgs[0], ogle.FormatBytes(uint64(len(data))))
+		return
+	}
+	if err := setThumbnail(ctx, yt, video, data); err != nil {
+		fatal(err)
+	}
+	log.Printf("Thumbnail updated")
+}
+
+// loadThumbnail reads and validates the image file. Invalid images are
+// converted into a valid thumbnail when -fit is set.
+func loadThumbnail(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	_, err = ogle.ValidateThumbnail(data)
+	if err == nil {
+		return data, nil
+	}
+	if _, invalid := err.(ogle.ThumbnailError); !invalid || !thumbnailFit {
+		return nil, &ogle.Failure{
+			Reason:   "invalidThumbnail",
+			Message:  fmt.Sprintf("%s: %v", file, err),
+			Hint:     "use -fit, or -fit-thumbnail when uploading, to crop and resize the image",
+			ExitCode: ogle.ExitUsage,
+			Err:      err,
+		}
+	}
+	log.Printf("Fitting %s into a %dx%d thumbnail: %v", file, ogle.ThumbnailWidth, ogle.ThumbnailHeight, err)
+	return ogle.FitThumbnail(data)
+}
+
+// setThumbnail uploads the image as the custom thumbnail of the video.
+func setThumbnail(ctx context.Context, yt *youtube.Service, videoID string, data []byte) error {
+	media := bytes.NewReader(data)
+	contentType := googleapi.ContentType(http.DetectContentType(data))
+	if _, err := yt.Thumbnails.Set(videoID).Media(media, contentType).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("unable to set thumbnail of video %s: %w", videoID, err)
+	}
+	return nil
+}
+
+func getThumbnails(yt *youtube.Service) {
+	sizes, err := parseThumbnailSizes(thumbnailSize)
+	if err != nil {
+		fatal(err)
+	}
+	if video == stdinArg && len(commandArgs) > 0 {
+		fatal(ogle.UsageError("unexpected arguments with -video -: %v", strings.Join(commandArgs, " ")))
+	}
+	ids := commandArgs
+	if video != "" {
+		ids = append([]string{video}, ids...)
+	}
+	var input io.Reader = os.Stdin
+	if video != stdinArg {
+		if len(ids) == 0 {
+			fatal(ogle.UsageError("No video_id provided. Use the -video flag or pass the videos as arguments."))
+		}
+		input = strings.NewReader(strings.Join(ids, "\n"))
+	}
+	runBatchFrom(input, "video", ogle.ParseVideoID, func(ctx context.Context, in ogle.Input, id string) (string, error) {
+		return downloadThumbnails(ctx, yt, id, sizes)
+	})
+}
+
+// parseThumbnailSizes validates the -size option. An empty list selects the
+// largest thumbnail available.
+func parseThumbnailSizes(s string) ([]string, error) {
+	switch s {
+	case "best":
+		return nil, nil
+	case "all":
+		return thumbnailSizes, nil
+	}
+	sizes := make([]string, 0)
+	for _, size := range strings.Split(s, ",") {
+		size = strings.TrimSpace(size)
+		if thumbnailURL(&youtube.ThumbnailDetails{}, size) == nil {
+			return nil, ogle.UsageError("invalid thumbnail size %q, use best, all or one of: %s", size, strings.Join(thumbnailSizes, ", "))
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// thumbnailURL returns a pointer to the thumbnail of the given size, or nil if
+// the size is unknown.
+func thumbnailURL(t *youtube.ThumbnailDetails, size string) **youtube.Thumbnail {
+	switch size {
+	case "maxres":
+		return &t.Maxres
+	case "standard":
+		return &t.Standard
+	case "high":
+		return &t.High
+	case "medium":
+		return &t.Medium
+	case "default":
+		return &t.Default
+	}
+	return nil
+}
+
+// downloadThumbnails saves the thumbnails of the video in -dir, named after
+// the video ID and size, returning the saved file names.
+func downloadThumbnails(ctx context.Context, yt *youtube.Service, id string, sizes []string) (string, error) {
+	resp, err := yt.Videos.List([]string{"snippet"}).Id(id).Fields("items(id,snippet/thumbnails)").Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Items) == 0 {
+		return "", ogle.NewFailure("videoNotFound", fmt.Errorf("No vídeos matched the provided id '%s'", id))
+	}
+	thumbs := resp.Items[0].Snippet.Thumbnails
+	if thumbs == nil {
+		thumbs = &youtube.ThumbnailDetails{}
+	}
+
+	best := len(sizes) == 0
+	if best {
+		sizes = thumbnailSizes
+	}
+	files := make([]string, 0)
+	for _, size := range sizes {
+		t := *thumbnailURL(thumbs, size)
+		if t == nil || t.Url == "" {
+			continue
+		}
+		ext := path.Ext(t.Url)
+		if ext == "" {
+			ext = ".jpg"
+		}
+		file := filepath.Join(thumbnailDir, id+"-"+size+ext)
+		if !dryRun {
+			if err := download(ctx, t.Url, file); err != nil {
+				return "", err
+			}
+		}
+		files = append(files, file)
+		if best {
+			break
+		}
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no thumbnail of size %s found", strings.Join(sizes, ", "))
+	}
+	if dryRun {
+		return "dry-run: " + strings.Join(files, ", "), nil
+	}
+	return "saved " + strings.Join(files, ", "), nil
+}
+
+// download saves the content of url into file. Thumbnails are public, so the
+// request is not authorized.
+func download(ctx context.Context, url, file string) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to download %s: %s", url, resp.Status)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ronoaldo/ogle"
+	"golang.org/x/net/context"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Upload command line options
+var (
+	videoPrivacy       = "private"
+	chunkSize    int64 = 8
+)
+
+func uploadFlags(fs *flag.FlagSet) {
+	videoEditFlags(fs)
+	fs.StringVar(&videoPrivacy, "privacy", "private", "The `status` of the uploaded video: private, unlisted or public.")
+	fs.StringVar(&playlist, "playlist", "", "The `playlist` to add the uploaded video to: an ID or playlist URL.")
+	fs.BoolVar(&resume, "resume", false, "Continue an interrupted upload of the same file.")
+	fs.Int64Var(&chunkSize, "chunk-size", 8, "Size of each upload request, in `megabytes`.")
+	fs.BoolVar(&thumbnailFit, "fit-thumbnail", false, "Crop, resize and re-encode the thumbnail if it is not valid.")
+}
+
+func uploadVideo(yt *youtube.Service) {
+	if len(commandArgs) != 1 {
+		fatal(ogle.UsageError("expected one video file to upload"))
+	}
+	file := commandArgs[0]
+	meta, err := loadSidecar(file)
+	if err != nil {
+		fatal(err)
+	}
+	if dryRun {
+		if err := meta.validate(file); err != nil {
+			fatal(err)
+		}
+		log.Printf("Dry run: %s would be uploaded as %q (%s)", file, meta.video(file, string(meta.Category)).Snippet.Title, meta.Privacy)
+		return
+	}
+
+	uploaded, err := publish(ctx, yt, file, meta)
+	if meta.file != "" {
+		meta.record(uploaded, err)
+		if err := meta.save(file); err != nil {
+			log.Printf("Unable to record upload in %s: %v", meta.file, err)
+		}
+	}
+	if err != nil {
+		fatal(err)
+	}
+	defer closeOutput()
+	if !noHeaders {
+		w.Header("ID", "URL")
+	}
+	w.Row(uploaded, uploaded.Id, "https://youtu.be/"+uploaded.Id)
+}
+
+// publish checks the sidecar metadata and uploads the video file with it,
+// then sets its thumbnail and adds it to the playlists. The uploaded video is returned even
+// if one of the later steps fails.
+func publish(ctx context.Context, yt *youtube.Service, file string, meta *sidecar) (*youtube.Video, error) {
+	if err := meta.validate(file); err != nil {
+		return nil, err
+	}
+	// The sidecar keeps the category as given, which may be a name.
+	category, err := categoryID(ctx, yt, string(meta.Category))
+	if err != nil {
+		return nil, err
+	}
+	var thumb []byte
+	if name := meta.thumbnailFile(file); name != "" {
+		// Check the thumbnail before spending time uploading the video.
+		var err error
+		if thumb, err = loadThumbnail(name); err != nil {
+			return nil, err
+		}
+	}
+	uploaded, err := upload(ctx, yt, file, meta.video(file, category))
+	if err != nil {
+		return nil, err
+	}
+	if thumb != nil {
+		if err := setThumbnail(ctx, yt, uploaded.Id, thumb); err != nil {
+			return uploaded, err
+		}
+	}
+	for _, p := range meta.Playlists {
+		id, err := ogle.ParsePlaylistID(p)
+		if err != nil {
+			return uploaded, err
+		}
+		if err := addToPlaylist(ctx, yt, id, uploaded.Id); err != nil {
+			return uploaded, err
+		}
+	}
+	return uploaded, nil
+}
+
+// upload sends the file as a new video, showing the progress. The upload
+// session is saved so an interrupted upload continues from where it stopped
+// when -resume is set.
+func upload(ctx context.Context, yt *youtube.Service, file string, v *youtube.Video) (*youtube.Video, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	sessionFile, err := ogle.UploadSessionFile("youtube", file)
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{"snippet", "status"}
+	if len(v.Localizations) > 0 {
+		parts = append(parts, "localizations")
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(file))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	u := &ogle.ResumableUpload{
+		Client:      httpClient,
+		URL:         googleapi.ResolveRelative(yt.BasePath, "/upload/youtube/v3/videos") + "?part=" + strings.Join(parts, ","),
+		Metadata:    v,
+		Media:       f,
+		Size:        fi.Size(),
+		ContentType: contentType,
+		ChunkSize:   chunkSize * 1024 * 1024,
+		OnSession: func(uri string) error {
+			abs, _ := filepath.Abs(file)
+			return ogle.SaveUploadSession(sessionFile, &ogle.UploadSession{URI: uri, File: abs, CreatedAt: time.Now()})
+		},
+	}
+	if resume {
+		s, err := ogle.LoadUploadSession(sessionFile)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			log.Printf("Resuming upload of %s", file)
+			u.SessionURI = s.URI
+		}
+	}
+
+	log.Printf("Uploading %s (%s)", file, ogle.FormatBytes(uint64(fi.Size())))
+	bar := ogle.NewProgressBar(os.Stderr, fi.Size())
+	u.Progress = bar.Update
+	body, err := u.Do(ctx)
+	if errors.Is(err, ogle.ErrSessionExpired) && u.SessionURI != "" {
+		log.Printf("Upload session expired, starting over")
+		u.SessionURI = ""
+		body, err = u.Do(ctx)
+	}
+	if err != nil {
+		bar.Done()
+		return nil, fmt.Errorf("upload of %s interrupted, use -resume to continue: %w", file, err)
+	}
+	bar.Update(fi.Size())
+	bar.Done()
+	if err := ogle.RemoveUploadSession(sessionFile); err != nil {
+		log.Printf("Unable to remove upload session: %v", err)
+	}
+
+	uploaded := new(youtube.Video)
+	if err := json.Unmarshal(body, uploaded); err != nil {
+		return nil, fmt.Errorf("invalid upload response: %w", err)
+	}
+	log.Printf("Uploaded %s as https://youtu.be/%s", file, uploaded.Id)
+	return uploaded, nil
+}
+
+// addToPlaylist appends the video to the playlist.
+func addToPlaylist(ctx context.Context, yt *youtube.Service, playlist, videoID string) error {
+	item := &youtube.PlaylistItem{
+		Snippet: &youtube.PlaylistItemSnippet{
+			PlaylistId: playlist,
+			ResourceId: &youtube.ResourceId{Kind: "youtube#video", VideoId: videoID},
+		},
+	}
+	if _, err := yt.PlaylistItems.Insert([]string{"snippet"}, item).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("unable to add video %s to playlist %s: %w", videoID, playlist, err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ronoaldo/ogle"
+	"golang.org/x/net/context"
+	"google.golang.org/api/youtube/v3"
+)
+
+// Upload watch command line options
+var (
+	watchInterval = 30 * time.Second
+	watchSettle   = time.Minute
+	watchOnce     bool
+)
+
+// videoExtensions are the extensions of the files uploaded from a watched
+// directory.
+var videoExtensions = map[string]bool{
+	".mp4": true, ".m4v": true, ".mov": true, ".mkv": true, ".webm": true,
+	".avi": true, ".wmv": true, ".flv": true, ".mpg": true, ".mpeg": true,
+}
+
+func watchFlags(fs *flag.FlagSet) {
+	fs.DurationVar(&watchInterval, "interval", 30*time.Second, "How often the directory is checked for new files.")
+	fs.DurationVar(&watchSettle, "settle", time.Minute, "How long a file must be left unchanged to be considered complete.")
+	fs.BoolVar(&watchOnce, "once", false, "Upload the complete files once and exit, instead of watching the directory.")
+}
+
+// watchUploads uploads the complete video files dropped in a directory, then
+// moves them, and their sidecars, to the done or failed subdirectories.
+func watchUploads(yt *youtube.Service) {
+	if len(commandArgs) != 1 {
+		fatal(ogle.UsageError("expected one directory to watch"))
+	}
+	dir := commandArgs[0]
+	for _, sub := range []string{"done", "failed"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			fatal(err)
+		}
+	}
+	// Uploads interrupted when the command stops continue on the next run.
+	resume = true
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	defer closeOutput()
+	if !noHeaders {
+		w.Header("FILE", "STATUS", "ID", "ERROR")
+	}
+	log.Printf("Watching %s for new videos", dir)
+	for {
+		files, err := completeFiles(dir)
+		if err != nil {
+			fatal(err)
+		}
+		if dryRun {
+			for _, file := range files {
+				log.Printf("Dry run: %s would be uploaded", file)
+			}
+			return
+		}
+		for _, file := range files {
+			if ctx.Err() != nil {
+				break
+			}
+			uploadWatched(ctx, yt, dir, file)
+			if err := w.Flush(); err != nil {
+				fatal(err)
+			}
+		}
+		if watchOnce {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			log.Printf("Stopped watching %s", dir)
+			return
+		case <-time.After(watchInterval):
+		}
+	}
+}
+
+// completeFiles returns the video files in dir, and their sidecars, that were
+// not modified in the last -settle duration.
+func completeFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	for _, e := range entries {
+		if !e.Type().IsRegular() || !videoExtensions[strings.ToLower(filepath.Ext(e.Name()))] {
+			continue
+		}
+		file := filepath.Join(dir, e.Name())
+		if settled(file) && (ogle.FindSidecar(file) == "" || settled(ogle.FindSidecar(file))) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func settled(file string) bool {
+	fi, err := os.Stat(file)
+	return err == nil && time.Since(fi.ModTime()) >= watchSettle
+}
+
+// uploadWatched uploads one file, recording the result in its sidecar, and
+// moves both to the done or failed subdirectory.
+func uploadWatched(ctx context.Context, yt *youtube.Service, dir, file string) {
+	var uploaded *youtube.Video
+	meta, err := loadSidecar(file)
+	if err != nil {
+		// The invalid sidecar is moved along with the video, unchanged.
+		meta = &sidecar{file: ogle.FindSidecar(file)}
+	} else {
+		uploaded, err = publish(ctx, yt, file, meta)
+		if ctx.Err() != nil {
+			// Interrupted: the file stays in place to be resumed later.
+			return
+		}
+		meta.record(uploaded, err)
+		if serr := meta.save(file); serr != nil {
+			log.Printf("Unable to record upload in %s: %v", meta.file, serr)
+		}
+	}
+
+	// A video uploaded with errors in the later steps, like adding it to a
+	// playlist, is done, so it is not uploaded again.
+	status, id, msg := "failed", "", ""
+	if uploaded != nil {
+		status, id = "done", uploaded.Id
+	}
+	if err != nil {
+		msg = ogle.Explain(err).Message
+		log.Printf("Upload of %s failed: %v", file, err)
+	}
+	w.Row(map[string]string{"file": file, "status": status, "id": id, "error": msg}, filepath.Base(file), status, id, msg)
+
+	for _, f := range []string{file, meta.file} {
+		if f == "" {
+			continue
+		}
+		if err := os.Rename(f, filepath.Join(dir, status, filepath.Base(f))); err != nil {
+			log.Printf("Unable to move %s: %v", f, err)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package ogle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Column describes a value printed for resources of type T in listings.
+type Column[T any] struct {
+	// Name identifies the column when selecting which ones to print.
+	Name string
+
+	// Header is the column title.
+	Header string
+
+	// Value extracts the column value from a resource. A column with a nil
+	// Value prints the row number.
+	Value func(T) interface{}
+
+	// Fields lists the comma separated resource fields read by Value, using
+	// the partial response syntax, like "snippet/title".
+	Fields string
+
+	// Extra columns are only printed when explicitly selected.
+	Extra bool
+}
+
+// Columns is the ordered list of columns available for a resource type.
+type Columns[T any] []Column[T]
+
+// Select returns the columns matching the comma separated list of names, in
+// the given order. An empty list selects all columns not marked as Extra.
+func (c Columns[T]) Select(names string) (Columns[T], error) {
+	if strings.TrimSpace(names) == "" {
+		sel := make(Columns[T], 0, len(c))
+		for _, col := range c {
+			if !col.Extra {
+				sel = append(sel, col)
+			}
+		}
+		return sel, nil
+	}
+
+	byName := make(map[string]Column[T], len(c))
+	for _, col := range c {
+		byName[col.Name] = col
+	}
+	sel := make(Columns[T], 0)
+	for _, name := range strings.Split(names, ",") {
+		col, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("ogle: unknown column %q, use one of: %s",
+				name, strings.Join(c.Names(), ", "))
+		}
+		sel = append(sel, col)
+	}
+	return sel, nil
+}
+
+// Names returns the names of all columns.
+func (c Columns[T]) Names() []string {
+	names := make([]string, 0, len(c))
+	for _, col := range c {
+		names = append(names, col.Name)
+	}
+	return names
+}
+
+// Headers returns the header of each column.
+func (c Columns[T]) Headers() []string {
+	headers := make([]string, 0, len(c))
+	for _, col := range c {
+		headers = append(headers, col.Header)
+	}
+	return headers
+}
+
+// Fields returns the resource fields needed to print the columns. It returns
+// nil if any column does not declare its fields.
+func (c Columns[T]) Fields() []string {
+	fields := make([]string, 0, len(c))
+	seen := make(map[string]bool)
+	for _, col := range c {
+		if col.Value == nil {
+			continue
+		}
+		if col.Fields == "" {
+			return nil
+		}
+		for _, f := range strings.Split(col.Fields, ",") {
+			if f = strings.TrimSpace(f); !seen[f] {
+				seen[f] = true
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// ListFields returns the partial response mask of a list call that returns
+// only the given item fields, like "nextPageToken,items(id,snippet/title)".
+// Duplicated fields are removed.
+func ListFields(fields ...string) googleapi.Field {
+	unique := make([]string, 0, len(fields))
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" && !seen[f] {
+			seen[f] = true
+			unique = append(unique, f)
+		}
+	}
+	return googleapi.Field("nextPageToken,items(" + strings.Join(unique, ",") + ")")
+}
+
+// Values returns the value of each column for the resource at row n.
+func (c Columns[T]) Values(n int, v T) []interface{} {
+	values := make([]interface{}, 0, len(c))
+	for _, col := range c {
+		if col.Value == nil {
+			values = append(values, n)
+			continue
+		}
+		values = append(values, col.Value(v))
+	}
+	return values
+}
+
+// templateFormatter prints each resource using a Go template.
+type templateFormatter struct {
+	w   io.Writer
+	t   *template.Template
+	err error
+}
+
+// NewTemplateFormatter initializes a Formatter that executes the Go template
+// text for each resource, like '{{.Id}} {{.Snippet.Title}}'. A new line is
+// added after each resource unless the template already ends with one.
+func NewTemplateFormatter(w io.Writer, text string) (Formatter, error) {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	t, err := template.New("format").Funcs(template.FuncMap{
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("ogle: invalid format template: %v", err)
+	}
+	return &templateFormatter{w: w, t: t}, nil
+}
+
+func (f *templateFormatter) Header(columns ...string) {}
+
+func (f *templateFormatter) Row(v interface{}, values ...interface{}) {
+	if f.err != nil {
+		return
+	}
+	f.err = f.t.Execute(f.w, v)
+}
+
+func (f *templateFormatter) Flush() error { return f.err }
+func (f *templateFormatter) Close() error { return f.err }
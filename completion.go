@@ -0,0 +1,162 @@
+package ogle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CompleteCommand is the hidden command that shell completion scripts call to
+// get the candidates for the word being completed. It receives the words typed
+// after the program name, the last one being the partial word, and prints one
+// candidate per line, optionally followed by a tab and its description.
+const CompleteCommand = "__complete"
+
+// CompletionItem is a value suggested by the shell completion.
+type CompletionItem struct {
+	// Value is the text inserted in the command line.
+	Value string `json:"value"`
+
+	// Description is shown next to the value by shells that support it.
+	Description string `json:"description,omitempty"`
+}
+
+// String formats the item as printed by the CompleteCommand.
+func (c CompletionItem) String() string {
+	if c.Description == "" {
+		return c.Value
+	}
+	return c.Value + "\t" + clean(c.Description)
+}
+
+// CompletionFile returns the file name where the values of the given kind,
+// like "playlists", are cached for completion. Each account selected with
+// SetAccount has its own values.
+func CompletionFile(api, kind string) string {
+	return filepath.Join(accountCacheDir(api), "completion", kind+".json")
+}
+
+// LoadCompletions reads the cached values of the given kind. It returns nil if
+// nothing was cached yet.
+func LoadCompletions(api, kind string) ([]CompletionItem, error) {
+	filename := CompletionFile(api, kind)
+	b, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items []CompletionItem
+	if err := json.Unmarshal(b, &items); err != nil {
+		return nil, fmt.Errorf("ogle: invalid completion cache %v: %v", filename, err)
+	}
+	return items, nil
+}
+
+// SaveCompletions replaces the cached values of the given kind.
+func SaveCompletions(api, kind string, items []CompletionItem) error {
+	filename := CompletionFile(api, kind)
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("ogle: unable to create completion cache dir: %v", err)
+	}
+	b, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// MergeCompletions adds the items to the cached values of the given kind,
+// updating the description of values already cached.
+func MergeCompletions(api, kind string, items []CompletionItem) error {
+	cached, err := LoadCompletions(api, kind)
+	if err != nil {
+		return err
+	}
+	index := make(map[string]int, len(cached))
+	for i, c := range cached {
+		index[c.Value] = i
+	}
+	for _, item := range items {
+		if i, ok := index[item.Value]; ok {
+			cached[i] = item
+			continue
+		}
+		index[item.Value] = len(cached)
+		cached = append(cached, item)
+	}
+	return SaveCompletions(api, kind, cached)
+}
+
+// FilterCompletions returns the items whose value starts with prefix.
+func FilterCompletions(items []CompletionItem, prefix string) []CompletionItem {
+	filtered := make([]CompletionItem, 0, len(items))
+	for _, item := range items {
+		if strings.HasPrefix(item.Value, prefix) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// CompletionShells are the shells supported by CompletionScript.
+var CompletionShells = []string{"bash", "zsh", "fish"}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for %[1]s.
+# Load it with: source <(%[1]s completion bash)
+_%[2]s_complete() {
+	local IFS=$'\n'
+	COMPREPLY=($(%[1]s %[3]s "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1))
+}
+complete -o default -F _%[2]s_complete %[1]s
+`,
+	"zsh": `#compdef %[1]s
+# zsh completion for %[1]s.
+# Load it with: source <(%[1]s completion zsh)
+_%[2]s_complete() {
+	local -a items
+	local line
+	for line in "${(@f)$(%[1]s %[3]s "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+		[[ -n $line ]] || continue
+		if [[ $line == *$'\t'* ]]; then
+			items+=("${${line%%%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+		else
+			items+=("${line//:/\\:}")
+		fi
+	done
+	_describe -t values '%[1]s' items
+}
+compdef _%[2]s_complete %[1]s
+`,
+	"fish": `# fish completion for %[1]s.
+# Load it with: %[1]s completion fish | source
+function __%[2]s_complete
+	set -l args (commandline -opc)[2..-1] (commandline -ct)
+	%[1]s %[3]s $args 2>/dev/null
+end
+complete -c %[1]s -f -a '(__%[2]s_complete)'
+`,
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// CompletionScript returns the completion script of the program for the given
+// shell. The script calls the program with the CompleteCommand to get the
+// candidates, so the program must implement it.
+func CompletionScript(shell, program string) (string, error) {
+	script, ok := completionScripts[shell]
+	if !ok {
+		return "", fmt.Errorf("ogle: unsupported shell %q, use one of: %s",
+			shell, strings.Join(CompletionShells, ", "))
+	}
+	return fmt.Sprintf(script, program, nonIdentifier.ReplaceAllString(program, "_"), CompleteCommand), nil
+}
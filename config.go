@@ -0,0 +1,133 @@
+package ogle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// ConfigDir returns the directory where the configuration of the given API is
+// stored. The directory is not created by this function.
+func ConfigDir(api string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return filepath.Join(os.Getenv("HOME"), "Library", "Application Support", "ogle", api)
+	case "linux", "freebsd":
+		if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+			return filepath.Join(dir, "ogle", api)
+		}
+		return filepath.Join(os.Getenv("HOME"), ".config", "ogle", api)
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "ogle", api)
+	}
+	return filepath.Join(".", "ogle-config", api)
+}
+
+// ConfigFile returns the name of the configuration file of the given API.
+func ConfigFile(api string) string {
+	return filepath.Join(ConfigDir(api), "config.json")
+}
+
+// Config holds user settings, like the default output format, as key and
+// value pairs. Settings can be grouped in named profiles that override the
+// defaults.
+type Config struct {
+	// Defaults are the settings used when no profile overrides them.
+	Defaults map[string]string `json:"defaults,omitempty"`
+
+	// Profiles are named groups of settings.
+	Profiles map[string]map[string]string `json:"profiles,omitempty"`
+}
+
+// LoadConfig reads the configuration file of the given API. An empty Config
+// is returned if the file does not exist.
+func LoadConfig(api string) (*Config, error) {
+	filename := ConfigFile(api)
+	c := new(Config)
+	b, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("ogle: invalid config file %v: %v", filename, err)
+	}
+	return c, nil
+}
+
+// SaveConfig writes the configuration file of the given API.
+func SaveConfig(api string, c *Config) error {
+	filename := ConfigFile(api)
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("ogle: unable to create config dir: %v", err)
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// HasProfile reports if the named profile exists.
+func (c *Config) HasProfile(profile string) bool {
+	_, ok := c.Profiles[profile]
+	return ok
+}
+
+// ProfileNames returns the sorted names of all profiles.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the value of key in the given profile, falling back to the
+// defaults. An empty profile selects only the defaults.
+func (c *Config) Get(profile, key string) (string, bool) {
+	if v, ok := c.Profiles[profile][key]; ok && profile != "" {
+		return v, true
+	}
+	v, ok := c.Defaults[key]
+	return v, ok
+}
+
+// Set changes the value of key in the given profile, or in the defaults if
+// profile is empty. An empty value removes the key.
+func (c *Config) Set(profile, key, value string) {
+	settings := c.Defaults
+	if profile != "" {
+		settings = c.Profiles[profile]
+	}
+	if value == "" {
+		delete(settings, key)
+		if profile != "" && len(settings) == 0 {
+			delete(c.Profiles, profile)
+		}
+		return
+	}
+	if settings == nil {
+		settings = make(map[string]string)
+		if profile == "" {
+			c.Defaults = settings
+		} else {
+			if c.Profiles == nil {
+				c.Profiles = make(map[string]map[string]string)
+			}
+			c.Profiles[profile] = settings
+		}
+	}
+	settings[key] = value
+}
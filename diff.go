@@ -0,0 +1,109 @@
+package ogle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Change is the old and new value of a resource field.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Changes lists the fields modified by an update.
+type Changes []Change
+
+// Add records the change of field, if the old and new values differ.
+func (c *Changes) Add(field, old, new string) {
+	if old != new {
+		*c = append(*c, Change{Field: field, Old: old, New: new})
+	}
+}
+
+// AddList records the change of a list field, like tags, if the old and new
+// lists differ. Lists are compared and printed joined by commas.
+func (c *Changes) AddList(field string, old, new []string) {
+	c.Add(field, strings.Join(old, ", "), strings.Join(new, ", "))
+}
+
+// Fields returns the names of the changed fields.
+func (c Changes) Fields() []string {
+	fields := make([]string, 0, len(c))
+	for _, ch := range c {
+		fields = append(fields, ch.Field)
+	}
+	return fields
+}
+
+// Print writes a field-level diff of the changes. Multi-line values, like
+// descriptions, are compared line by line, so only the modified lines are
+// marked with "-" and "+".
+func (c Changes) Print(w io.Writer) error {
+	var b strings.Builder
+	for _, ch := range c {
+		fmt.Fprintf(&b, "%s:\n", ch.Field)
+		for _, line := range DiffLines(ch.Old, ch.New) {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// DiffLines compares the text line by line, returning every line prefixed by
+// "- " if it was removed, "+ " if it was added or "  " if it is unchanged.
+func DiffLines(old, new string) []string {
+	a, b := splitLines(old), splitLines(new)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
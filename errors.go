@@ -0,0 +1,266 @@
+package ogle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+// Exit codes used by ogle commands, so wrapper scripts can branch on the kind
+// of failure.
+const (
+	ExitOK        = 0 // Success.
+	ExitError     = 1 // Unclassified failure.
+	ExitUsage     = 2 // Invalid command line arguments.
+	ExitAuth      = 3 // Missing, expired or revoked credentials.
+	ExitForbidden = 4 // The user is not allowed to perform the operation.
+	ExitNotFound  = 5 // The requested resource does not exist.
+	ExitQuota     = 6 // The API quota or rate limit was exceeded.
+	ExitInvalid   = 7 // The API rejected the request as invalid.
+)
+
+// Failure is a classified error, carrying a human-readable message, a hint on
+// how to fix it and the exit code the command should use.
+type Failure struct {
+	// Reason is a stable identifier of the failure, like "quotaExceeded".
+	Reason string `json:"reason"`
+
+	// Message is a human-readable description of the failure.
+	Message string `json:"message"`
+
+	// Hint suggests how to fix the problem, if known.
+	Hint string `json:"hint,omitempty"`
+
+	// ExitCode is the process exit status for this failure.
+	ExitCode int `json:"exitCode"`
+
+	// HTTPStatus is the HTTP status code returned by the API, if any.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+
+	// Violations lists the invalid metadata fields, for reason
+	// "invalidMetadata".
+	Violations []Violation `json:"violations,omitempty"`
+
+	// Err is the original error.
+	Err error `json:"-"`
+}
+
+// Error implements the error interface.
+func (f *Failure) Error() string {
+	if f.Hint == "" {
+		return f.Message
+	}
+	return f.Message + " (" + f.Hint + ")"
+}
+
+// Unwrap returns the original error.
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+// UsageError returns a Failure reporting invalid command line arguments.
+func UsageError(format string, args ...interface{}) *Failure {
+	msg := fmt.Sprintf(format, args...)
+	return &Failure{
+		Reason:   "usage",
+		Message:  msg,
+		ExitCode: ExitUsage,
+		Err:      errors.New(msg),
+	}
+}
+
+type failureInfo struct {
+	message string
+	hint    string
+	code    int
+}
+
+// knownReasons maps the reasons reported by Google APIs to friendly messages.
+var knownReasons = map[string]failureInfo{
+	"quotaExceeded": {
+		"The API quota for this project was exceeded",
+		"wait until the daily quota resets or use -cache to reduce quota usage",
+		ExitQuota,
+	},
+	"rateLimitExceeded": {
+		"Too many requests were sent in a short period",
+		"retry later or slow down with -rate",
+		ExitQuota,
+	},
+	"userRateLimitExceeded": {
+		"Too many requests were sent for this user",
+		"retry later or slow down with -rate",
+		ExitQuota,
+	},
+	"forbidden": {
+		"The operation is not allowed for the authenticated user",
+		"check that you are logged in with the account that owns the resource",
+		ExitForbidden,
+	},
+	"insufficientPermissions": {
+		"The credentials do not grant access to this operation",
+		"log out and authorize again to grant the required permissions",
+		ExitForbidden,
+	},
+	"videoNotFound": {
+		"The video was not found",
+		"check the video ID and that it was not deleted",
+		ExitNotFound,
+	},
+	"playlistNotFound": {
+		"The playlist was not found",
+		"check the playlist ID and that it is visible to you",
+		ExitNotFound,
+	},
+	"channelNotFound": {
+		"The channel was not found",
+		"check the channel ID",
+		ExitNotFound,
+	},
+	"captionNotFound": {
+		"The caption track was not found",
+		"list the tracks of the video with 'youtube captions list'",
+		ExitNotFound,
+	},
+	"playlistItemsNotAccessible": {
+		"The playlist items are not accessible",
+		"private playlists can only be listed by their owner; check the account in use",
+		ExitForbidden,
+	},
+	"authError": {
+		"The credentials are invalid or have expired",
+		"log out and authorize again",
+		ExitAuth,
+	},
+	"invalidToken": {
+		"The saved authorization token is invalid or was revoked",
+		"log out and authorize again",
+		ExitAuth,
+	},
+	"aborted": {
+		"The operation was not confirmed",
+		"answer 'y' to the prompt or use -yes to skip it",
+		ExitError,
+	},
+}
+
+// Explain classifies the error, returning a Failure with a friendly message and
+// the exit code to use. Errors that are already a Failure are returned as is.
+func Explain(err error) *Failure {
+	if err == nil {
+		return nil
+	}
+	var f *Failure
+	if errors.As(err, &f) {
+		return f
+	}
+
+	var verr ValidationError
+	if errors.As(err, &verr) {
+		return &Failure{
+			Reason:     "invalidMetadata",
+			Message:    err.Error(),
+			ExitCode:   ExitInvalid,
+			Violations: verr,
+			Err:        err,
+		}
+	}
+
+	var rerr *oauth2.RetrieveError
+	if errors.As(err, &rerr) {
+		f := NewFailure("invalidToken", err)
+		f.Message = withContext(err, rerr, f.Message)
+		return f
+	}
+
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		for _, item := range gerr.Errors {
+			if _, ok := knownReasons[item.Reason]; ok {
+				f := NewFailure(item.Reason, err)
+				f.Message = withContext(err, gerr, f.Message)
+				f.HTTPStatus = gerr.Code
+				return f
+			}
+		}
+		reason := ""
+		if len(gerr.Errors) > 0 {
+			reason = gerr.Errors[0].Reason
+		}
+		f := &Failure{
+			Reason:     reason,
+			Message:    withContext(err, gerr, apiMessage(gerr)),
+			ExitCode:   exitCodeForStatus(gerr.Code),
+			HTTPStatus: gerr.Code,
+			Err:        err,
+		}
+		if f.Reason == "" {
+			f.Reason = strings.ReplaceAll(strings.ToLower(http.StatusText(gerr.Code)), " ", "")
+		}
+		return f
+	}
+
+	return &Failure{
+		Reason:   "error",
+		Message:  err.Error(),
+		ExitCode: ExitError,
+		Err:      err,
+	}
+}
+
+// NewFailure returns a Failure for one of the well known reasons, like
+// "videoNotFound", wrapping the provided error.
+func NewFailure(reason string, err error) *Failure {
+	info, ok := knownReasons[reason]
+	if !ok {
+		return &Failure{Reason: reason, Message: err.Error(), ExitCode: ExitError, Err: err}
+	}
+	return &Failure{
+		Reason:   reason,
+		Message:  info.message,
+		Hint:     info.hint,
+		ExitCode: info.code,
+		Err:      err,
+	}
+}
+
+// withContext prefixes msg with the context that err adds when wrapping inner,
+// like "unable to fetch video durations: ", so it is not lost when the
+// message of inner is replaced.
+func withContext(err, inner error, msg string) string {
+	s, suffix := err.Error(), inner.Error()
+	if s == suffix || !strings.HasSuffix(s, suffix) {
+		return msg
+	}
+	return strings.TrimSuffix(s, suffix) + msg
+}
+
+func apiMessage(gerr *googleapi.Error) string {
+	if gerr.Message != "" {
+		return gerr.Message
+	}
+	if len(gerr.Errors) > 0 && gerr.Errors[0].Message != "" {
+		return gerr.Errors[0].Message
+	}
+	return fmt.Sprintf("The API returned HTTP status %d", gerr.Code)
+}
+
+func exitCodeForStatus(status int) int {
+	switch status {
+	case http.StatusBadRequest:
+		return ExitInvalid
+	case http.StatusUnauthorized:
+		return ExitAuth
+	case http.StatusForbidden:
+		return ExitForbidden
+	case http.StatusNotFound:
+		return ExitNotFound
+	case http.StatusTooManyRequests:
+		return ExitQuota
+	}
+	return ExitError
+}
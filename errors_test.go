@@ -0,0 +1,172 @@
+package ogle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi"
+)
+
+func TestExplain(t *testing.T) {
+	apiError := func(code int, reason, message string) error {
+		gerr := &googleapi.Error{Code: code, Message: message}
+		if reason != "" {
+			gerr.Errors = []googleapi.ErrorItem{{Reason: reason, Message: message}}
+		}
+		return gerr
+	}
+	usage := UsageError("missing video ID")
+	violations := ValidationError{{Field: "title", Offset: 3, Message: "invalid character '<'"}}
+
+	tests := []struct {
+		name       string
+		err        error
+		reason     string
+		message    string
+		hint       bool
+		code       int
+		httpStatus int
+		violations []Violation
+	}{
+		{
+			name:       "known reason",
+			err:        apiError(403, "quotaExceeded", "The request cannot be completed"),
+			reason:     "quotaExceeded",
+			message:    "The API quota for this project was exceeded",
+			hint:       true,
+			code:       ExitQuota,
+			httpStatus: 403,
+		},
+		{
+			name:       "known reason keeps the wrapped context",
+			err:        fmt.Errorf("unable to fetch video durations: %w", apiError(404, "videoNotFound", "Not found")),
+			reason:     "videoNotFound",
+			message:    "unable to fetch video durations: The video was not found",
+			hint:       true,
+			code:       ExitNotFound,
+			httpStatus: 404,
+		},
+		{
+			name: "known reason after unknown ones",
+			err: &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{
+				{Reason: "somethingElse"}, {Reason: "insufficientPermissions"},
+			}},
+			reason:     "insufficientPermissions",
+			message:    "The credentials do not grant access to this operation",
+			hint:       true,
+			code:       ExitForbidden,
+			httpStatus: 403,
+		},
+		{
+			name:       "unknown reason falls back to the status",
+			err:        apiError(400, "invalidCategoryId", "The category is invalid"),
+			reason:     "invalidCategoryId",
+			message:    "The category is invalid",
+			code:       ExitInvalid,
+			httpStatus: 400,
+		},
+		{
+			name:       "unknown reason keeps the wrapped context",
+			err:        fmt.Errorf("unable to update video abc: %w", apiError(404, "", "Requested entity was not found")),
+			reason:     "notfound",
+			message:    "unable to update video abc: Requested entity was not found",
+			code:       ExitNotFound,
+			httpStatus: 404,
+		},
+		{name: "unauthorized", err: apiError(401, "", ""), reason: "unauthorized", message: "The API returned HTTP status 401", code: ExitAuth, httpStatus: 401},
+		{name: "too many requests", err: apiError(429, "", "slow down"), reason: "toomanyrequests", message: "slow down", code: ExitQuota, httpStatus: 429},
+		{name: "server error", err: apiError(503, "backendError", "try again"), reason: "backendError", message: "try again", code: ExitError, httpStatus: 503},
+		{
+			name:    "oauth2 retrieve error",
+			err:     fmt.Errorf("unable to refresh token: %w", &oauth2.RetrieveError{Response: &http.Response{StatusCode: 400}, Body: []byte("invalid_grant")}),
+			reason:  "invalidToken",
+			message: "unable to refresh token: The saved authorization token is invalid or was revoked",
+			hint:    true,
+			code:    ExitAuth,
+		},
+		{
+			name:       "validation error",
+			err:        violations,
+			reason:     "invalidMetadata",
+			message:    violations.Error(),
+			code:       ExitInvalid,
+			violations: violations,
+		},
+		{
+			name:       "wrapped validation error",
+			err:        fmt.Errorf("video 1: %w", violations),
+			reason:     "invalidMetadata",
+			message:    "video 1: " + violations.Error(),
+			code:       ExitInvalid,
+			violations: violations,
+		},
+		{name: "failure passthrough", err: usage, reason: "usage", message: "missing video ID", code: ExitUsage},
+		{name: "wrapped failure", err: fmt.Errorf("context: %w", usage), reason: "usage", message: "missing video ID", code: ExitUsage},
+		{name: "plain error", err: errors.New("disk full"), reason: "error", message: "disk full", code: ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Explain(tt.err)
+			if f.Reason != tt.reason {
+				t.Errorf("Reason %q, want %q", f.Reason, tt.reason)
+			}
+			if f.Message != tt.message {
+				t.Errorf("Message %q, want %q", f.Message, tt.message)
+			}
+			if got := f.Hint != ""; got != tt.hint {
+				t.Errorf("Hint %q, want hint %v", f.Hint, tt.hint)
+			}
+			if f.ExitCode != tt.code {
+				t.Errorf("ExitCode %d, want %d", f.ExitCode, tt.code)
+			}
+			if f.HTTPStatus != tt.httpStatus {
+				t.Errorf("HTTPStatus %d, want %d", f.HTTPStatus, tt.httpStatus)
+			}
+			if !reflect.DeepEqual([]Violation(f.Violations), tt.violations) {
+				t.Errorf("Violations %v, want %v", f.Violations, tt.violations)
+			}
+			if f.Err == nil {
+				t.Errorf("Failure does not wrap the original error")
+			}
+		})
+	}
+	if Explain(nil) != nil {
+		t.Errorf("Explain(nil) is not nil")
+	}
+	if f := Explain(usage); f != usage {
+		t.Errorf("Explain(Failure) = %p, want the same %p", f, usage)
+	}
+}
+
+func TestExitCodeForStatus(t *testing.T) {
+	tests := map[int]int{
+		200: ExitError,
+		400: ExitInvalid,
+		401: ExitAuth,
+		403: ExitForbidden,
+		404: ExitNotFound,
+		409: ExitError,
+		429: ExitQuota,
+		500: ExitError,
+	}
+	for status, want := range tests {
+		if got := exitCodeForStatus(status); got != want {
+			t.Errorf("exitCodeForStatus(%d) = %d, want %d", status, got, want)
+		}
+	}
+}
+
+func TestFailureError(t *testing.T) {
+	f := NewFailure("aborted", errors.New("no"))
+	if got, want := f.Error(), "The operation was not confirmed (answer 'y' to the prompt or use -yes to skip it)"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	f = NewFailure("custom", errors.New("something odd"))
+	if f.Error() != "something odd" || f.ExitCode != ExitError {
+		t.Errorf("unknown reason: got (%q, %d), want (\"something odd\", %d)", f.Error(), f.ExitCode, ExitError)
+	}
+}
@@ -0,0 +1,194 @@
+package ogle
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formatter writes a listing of API resources in a specific output format.
+//
+// Each row carries both the full API resource and the values of the printed
+// columns. Column based formats, like tables and CSV, print the values, while
+// structured formats, like JSON and YAML, encode the whole resource.
+type Formatter interface {
+	// Header writes the column names, if the format has a header.
+	Header(columns ...string)
+
+	// Row writes a single record, for resource v, with the given column
+	// values.
+	Row(v interface{}, values ...interface{})
+
+	// Flush writes any buffered data to the underlying io.Writer.
+	Flush() error
+
+	// Close finishes the output document and flushes it.
+	Close() error
+}
+
+// Formats lists the names accepted by NewFormatter.
+var Formats = []string{"table", "tsv", "csv", "json", "jsonl", "yaml"}
+
+// NewFormatter initializes the Formatter for the named format, writing to w.
+func NewFormatter(format string, w io.Writer) (Formatter, error) {
+	switch format {
+	case "", "table":
+		return NewTabWriter(w), nil
+	case "tsv":
+		return &tsvFormatter{w: bufio.NewWriter(w)}, nil
+	case "csv":
+		return &csvFormatter{w: csv.NewWriter(w)}, nil
+	case "json":
+		return &jsonFormatter{w: bufio.NewWriter(w)}, nil
+	case "jsonl":
+		return &jsonFormatter{w: bufio.NewWriter(w), lines: true}, nil
+	case "yaml":
+		return &yamlFormatter{w: bufio.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("ogle: unknown output format %q, use one of: %s",
+		format, strings.Join(Formats, ", "))
+}
+
+// formatValues formats each value with the '%v' format string. Nil values,
+// like a column the resource has no data for, are empty.
+func formatValues(values []interface{}) []string {
+	s := make([]string, 0, len(values))
+	for _, v := range values {
+		if isNil(v) {
+			s = append(s, "")
+			continue
+		}
+		s = append(s, fmt.Sprintf("%v", v))
+	}
+	return s
+}
+
+type tsvFormatter struct {
+	w *bufio.Writer
+}
+
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ")
+
+func (f *tsvFormatter) Header(columns ...string) {
+	f.writeLine(columns)
+}
+
+func (f *tsvFormatter) Row(v interface{}, values ...interface{}) {
+	f.writeLine(formatValues(values))
+}
+
+func (f *tsvFormatter) writeLine(fields []string) {
+	for i := range fields {
+		fields[i] = tsvEscaper.Replace(fields[i])
+	}
+	f.w.WriteString(strings.Join(fields, "\t") + "\n")
+}
+
+func (f *tsvFormatter) Flush() error { return f.w.Flush() }
+func (f *tsvFormatter) Close() error { return f.Flush() }
+
+type csvFormatter struct {
+	w *csv.Writer
+}
+
+func (f *csvFormatter) Header(columns ...string) {
+	f.w.Write(columns)
+}
+
+func (f *csvFormatter) Row(v interface{}, values ...interface{}) {
+	f.w.Write(formatValues(values))
+}
+
+func (f *csvFormatter) Flush() error {
+	f.w.Flush()
+	return f.w.Error()
+}
+
+func (f *csvFormatter) Close() error { return f.Flush() }
+
+// jsonFormatter writes resources as a JSON array or, when lines is set, as
+// one JSON document per line.
+type jsonFormatter struct {
+	w     *bufio.Writer
+	lines bool
+	rows  int
+	err   error
+}
+
+func (f *jsonFormatter) Header(columns ...string) {}
+
+func (f *jsonFormatter) Row(v interface{}, values ...interface{}) {
+	if f.err != nil {
+		return
+	}
+	var (
+		b   []byte
+		err error
+	)
+	if f.lines {
+		b, err = json.Marshal(v)
+	} else {
+		b, err = json.MarshalIndent(v, "  ", "  ")
+	}
+	if err != nil {
+		f.err = err
+		return
+	}
+	switch {
+	case f.lines:
+	case f.rows == 0:
+		f.w.WriteString("[\n  ")
+	default:
+		f.w.WriteString(",\n  ")
+	}
+	f.w.Write(b)
+	if f.lines {
+		f.w.WriteString("\n")
+	}
+	f.rows++
+}
+
+func (f *jsonFormatter) Flush() error {
+	if f.err != nil {
+		return f.err
+	}
+	return f.w.Flush()
+}
+
+func (f *jsonFormatter) Close() error {
+	if !f.lines {
+		if f.rows == 0 {
+			f.w.WriteString("[]\n")
+		} else {
+			f.w.WriteString("\n]\n")
+		}
+	}
+	return f.Flush()
+}
+
+// yamlFormatter writes resources as a YAML sequence.
+type yamlFormatter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (f *yamlFormatter) Header(columns ...string) {}
+
+func (f *yamlFormatter) Row(v interface{}, values ...interface{}) {
+	if f.err != nil {
+		return
+	}
+	f.err = EncodeYAML(f.w, []interface{}{v})
+}
+
+func (f *yamlFormatter) Flush() error {
+	if f.err != nil {
+		return f.err
+	}
+	return f.w.Flush()
+}
+
+func (f *yamlFormatter) Close() error { return f.Flush() }
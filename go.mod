@@ -3,9 +3,12 @@ module github.com/ronoaldo/ogle
 go 1.18
 
 require (
+	golang.org/x/image v0.14.0
 	golang.org/x/net v0.23.0
 	golang.org/x/oauth2 v0.7.0
+	golang.org/x/sys v0.18.0
 	google.golang.org/api v0.114.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -17,7 +20,6 @@ require (
 	github.com/googleapis/enterprise-certificate-proxy v0.2.3 // indirect
 	github.com/googleapis/gax-go/v2 v2.7.1 // indirect
 	go.opencensus.io v0.24.0 // indirect
-	golang.org/x/sys v0.18.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
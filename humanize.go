@@ -0,0 +1,202 @@
+package ogle
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Humanizer formats API values, like counts, timestamps and durations, in a
+// compact form that is easier to read. When Raw is set, values are printed as
+// returned by the API.
+type Humanizer struct {
+	// Raw disables the formatting.
+	Raw bool
+
+	// Relative prints timestamps relative to now, like "3 days ago".
+	Relative bool
+
+	// Location is the time zone used to print timestamps. If nil, the local
+	// time zone is used.
+	Location *time.Location
+}
+
+// TimeLayout is the layout used by Humanizer to print absolute timestamps.
+const TimeLayout = "2006-01-02 15:04"
+
+// Count formats n in a compact form, like 12.3K or 4.5M.
+func (h Humanizer) Count(n uint64) string {
+	if h.Raw {
+		return strconv.FormatUint(n, 10)
+	}
+	return FormatCount(n)
+}
+
+// Time formats an RFC 3339 timestamp in the configured time zone, or relative
+// to now. Values that cannot be parsed are returned unchanged.
+func (h Humanizer) Time(s string) string {
+	if h.Raw || s == "" {
+		return s
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	if h.Relative {
+		return RelativeTime(t, time.Now())
+	}
+	loc := h.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format(TimeLayout)
+}
+
+// Duration formats an ISO 8601 duration, like PT1H2M3S, as 1:02:03. Values
+// that cannot be parsed are returned unchanged.
+func (h Humanizer) Duration(s string) string {
+	if h.Raw || s == "" {
+		return s
+	}
+	d, err := ParseDuration(s)
+	if err != nil {
+		return s
+	}
+	return FormatDuration(d)
+}
+
+// Elapsed formats d as 1:02:03, or as an ISO 8601 duration when Raw is set.
+func (h Humanizer) Elapsed(d time.Duration) string {
+	if h.Raw {
+		return FormatISODuration(d)
+	}
+	return FormatDuration(d)
+}
+
+var countUnits = []string{"", "K", "M", "B", "T"}
+
+// FormatCount formats n with one decimal digit and a unit suffix, like 12.3K.
+// Numbers below one thousand are printed as is.
+func FormatCount(n uint64) string {
+	if n < 1000 {
+		return strconv.FormatUint(n, 10)
+	}
+	v := float64(n)
+	unit := 0
+	for v >= 999.95 && unit < len(countUnits)-1 {
+		v /= 1000
+		unit++
+	}
+	s := strconv.FormatFloat(math.Round(v*10)/10, 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0") + countUnits[unit]
+}
+
+var byteUnits = []string{"B", "KB", "MB", "GB", "TB", "PB"}
+
+// FormatBytes formats a size in bytes with one decimal digit and a binary
+// unit, like 1.5 GB.
+func FormatBytes(n uint64) string {
+	v := float64(n)
+	unit := 0
+	for v >= 1024 && unit < len(byteUnits)-1 {
+		v /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", v, byteUnits[unit])
+}
+
+// RelativeTime describes t relative to now, like "3 days ago" or "in 2 hours".
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "hour"
+	case d < 30*24*time.Hour:
+		n, unit = int(d/(24*time.Hour)), "day"
+	case d < 365*24*time.Hour:
+		n, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		n, unit = int(d/(365*24*time.Hour)), "year"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	if future {
+		return fmt.Sprintf("in %d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// ParseDuration parses an ISO 8601 duration as used by the YouTube API, like
+// PT1H2M3S or P1DT2H.
+func ParseDuration(s string) (time.Duration, error) {
+	m := isoDuration.FindStringSubmatch(s)
+	if m == nil || s == "P" || s == "PT" {
+		return 0, fmt.Errorf("ogle: invalid ISO 8601 duration %q", s)
+	}
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var d time.Duration
+	for i, unit := range units {
+		if m[i+1] == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("ogle: invalid ISO 8601 duration %q: %v", s, err)
+		}
+		d += time.Duration(v * float64(unit))
+	}
+	return d, nil
+}
+
+// FormatDuration formats d as hours, minutes and seconds, like 1:02:03, or as
+// minutes and seconds, like 4:05, when shorter than one hour.
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d/time.Minute) % 60
+	s := int(d/time.Second) % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// FormatISODuration formats d as an ISO 8601 duration, like PT1H2M3S.
+func FormatISODuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := int(d / time.Hour); h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+	}
+	if m := int(d/time.Minute) % 60; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if s := int(d/time.Second) % 60; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
@@ -0,0 +1,166 @@
+package ogle
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "PT1H2M3S", want: time.Hour + 2*time.Minute + 3*time.Second},
+		{in: "PT45S", want: 45 * time.Second},
+		{in: "PT4M", want: 4 * time.Minute},
+		{in: "PT1.5S", want: 1500 * time.Millisecond},
+		{in: "P0D", want: 0},
+		{in: "PT0S", want: 0},
+		{in: "P1DT2H", want: 26 * time.Hour},
+		{in: "P1W", want: 7 * 24 * time.Hour},
+		{in: "P1W2DT3H4M5S", want: 9*24*time.Hour + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{in: "", wantErr: true},
+		{in: "P", wantErr: true},
+		{in: "PT", wantErr: true},
+		{in: "1H2M", wantErr: true},
+		{in: "PT1H2M3", wantErr: true},
+		{in: "P1H", wantErr: true},
+		{in: "PT-1S", wantErr: true},
+		{in: "pt1h", wantErr: true},
+		{in: "PT1S ", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = (%v, %v), want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in      time.Duration
+		want    string
+		wantISO string
+	}{
+		{0, "0:00", "PT0S"},
+		{45 * time.Second, "0:45", "PT45S"},
+		{4*time.Minute + 5*time.Second, "4:05", "PT4M5S"},
+		{59*time.Minute + 59500*time.Millisecond, "1:00:00", "PT1H"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "1:02:03", "PT1H2M3S"},
+		{26 * time.Hour, "26:00:00", "PT26H"},
+		{1400 * time.Millisecond, "0:01", "PT1S"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.in); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := FormatISODuration(tt.in); got != tt.wantISO {
+			t.Errorf("FormatISODuration(%v) = %q, want %q", tt.in, got, tt.wantISO)
+		}
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		in   uint64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1K"},
+		{1234, "1.2K"},
+		{12345, "12.3K"},
+		{999949, "999.9K"},
+		{999950, "1M"},
+		{1500000, "1.5M"},
+		{999950000, "1B"},
+		{2000000000000, "2T"},
+		{5000000000000000, "5000T"},
+	}
+	for _, tt := range tests {
+		if got := FormatCount(tt.in); got != tt.want {
+			t.Errorf("FormatCount(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		in   uint64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{3 << 30, "3.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.in); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{0, "just now"},
+		{-30 * time.Second, "just now"},
+		{30 * time.Second, "just now"},
+		{-time.Minute, "1 minute ago"},
+		{-59 * time.Minute, "59 minutes ago"},
+		{-2 * time.Hour, "2 hours ago"},
+		{-day, "1 day ago"},
+		{-29 * day, "29 days ago"},
+		{-45 * day, "1 month ago"},
+		{-364 * day, "12 months ago"},
+		{-365 * day, "1 year ago"},
+		{-3 * 365 * day, "3 years ago"},
+		{5 * time.Minute, "in 5 minutes"},
+		{time.Hour, "in 1 hour"},
+		{3 * day, "in 3 days"},
+		{400 * day, "in 1 year"},
+	}
+	for _, tt := range tests {
+		if got := RelativeTime(now.Add(tt.offset), now); got != tt.want {
+			t.Errorf("RelativeTime(now%+v) = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestHumanizer(t *testing.T) {
+	h := Humanizer{Location: time.UTC}
+	raw := Humanizer{Raw: true}
+	tests := []struct {
+		got, want string
+	}{
+		{h.Count(1234), "1.2K"},
+		{raw.Count(1234), "1234"},
+		{h.Time("2024-06-15T12:30:00Z"), "2024-06-15 12:30"},
+		{Humanizer{Location: time.FixedZone("BRT", -3*3600)}.Time("2024-06-15T12:30:00Z"), "2024-06-15 09:30"},
+		{h.Time("yesterday"), "yesterday"},
+		{raw.Time("2024-06-15T12:30:00Z"), "2024-06-15T12:30:00Z"},
+		{h.Duration("PT1H2M3S"), "1:02:03"},
+		{h.Duration("bogus"), "bogus"},
+		{raw.Duration("PT1H2M3S"), "PT1H2M3S"},
+		{h.Elapsed(90 * time.Second), "1:30"},
+		{raw.Elapsed(90 * time.Second), "PT1M30S"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("case %d: got %q, want %q", i, tt.got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync"
 
 	"golang.org/x/net/context"
 	"golang.org/x/oauth2"
@@ -47,12 +48,52 @@ func NewClient(ctx context.Context, api string, scopes ...string) (c *http.Clien
 	return config.Client(ctx, token), nil
 }
 
+var (
+	accountsMu sync.Mutex
+	accounts   = make(map[string]string)
+)
+
+// SetAccount selects the named account used to authorize calls to the given
+// API. Each account has its own cached token, so users can switch between
+// them without logging in again. An empty name selects the default account.
+func SetAccount(api, account string) {
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+	accounts[api] = account
+}
+
+// Account returns the account selected with SetAccount for the given API.
+func Account(api string) string {
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+	return accounts[api]
+}
+
+// accountFileName returns the selected account for the given API with the
+// characters that are not safe in file names replaced by underscores.
+func accountFileName(api string) string {
+	account := []byte(Account(api))
+	for i, c := range account {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '@' || c == '+' || (c == '.' && i > 0):
+		default:
+			account[i] = '_'
+		}
+	}
+	return string(account)
+}
+
 func tokenCacheFileName(api string) string {
+	name := api
+	if account := accountFileName(api); account != "" {
+		name += "-" + account
+	}
 	switch runtime.GOOS {
 	case "darwin":
-		return filepath.Join(os.Getenv("HOME"), "Library", "Caches", api+".token")
+		return filepath.Join(os.Getenv("HOME"), "Library", "Caches", name+".token")
 	case "linux", "freebsd":
-		return filepath.Join(os.Getenv("HOME"), ".cache", "ogle-"+api+".token")
+		return filepath.Join(os.Getenv("HOME"), ".cache", "ogle-"+name+".token")
 	}
 	return "."
 }
@@ -0,0 +1,168 @@
+package ogle
+
+import (
+	"errors"
+	"reflect"
+
+	"golang.org/x/net/context"
+)
+
+// ErrStop can be returned by the callback given to Paginate to stop the
+// iteration early without reporting an error.
+var ErrStop = errors.New("ogle: stop pagination")
+
+// Pager is implemented by the list calls of the google-api-go generated
+// clients, like *youtube.PlaylistsListCall.
+type Pager[R any] interface {
+	Pages(ctx context.Context, f func(R) error) error
+}
+
+// Cursor identifies a position within a paged listing, allowing it to be
+// resumed later.
+type Cursor struct {
+	// PageToken is the token of the page holding the next item. It is empty
+	// for the first page.
+	PageToken string
+
+	// Offset is the number of items of that page already processed.
+	Offset int
+}
+
+// PageOptions controls how Paginate iterates over the results.
+type PageOptions struct {
+	// Limit is the maximum number of items to process. Zero means all items.
+	Limit int
+
+	// PageSize is the number of items requested per page. Zero uses the
+	// server default.
+	PageSize int64
+
+	// Start is the position where the iteration begins. The zero value
+	// starts from the first item.
+	Start Cursor
+
+	// OnPage, if not nil, is called after all items of a page are processed
+	// with the Cursor for the next page, or nil if it was the last one. If it
+	// returns an error, the iteration stops with that error.
+	OnPage func(next *Cursor) error
+}
+
+// Paginate calls f for every item of a paged listing. The items function
+// extracts the items from each response page. The response type must be given
+// explicitly, as it cannot be inferred from the call, e.g.:
+//
+//	req := yt.Playlists.List([]string{"id,snippet"}).Mine(true)
+//	next, err := ogle.Paginate[*youtube.PlaylistListResponse](ctx, req,
+//		func(r *youtube.PlaylistListResponse) []*youtube.Playlist { return r.Items },
+//		ogle.PageOptions{Limit: 10},
+//		func(p *youtube.Playlist) error {
+//			fmt.Println(p.Snippet.Title)
+//			return nil
+//		})
+//
+// It returns the Cursor for the next unprocessed item when the iteration is
+// stopped by the limit or by f returning ErrStop, or nil when all items were
+// processed.
+func Paginate[R, T any](ctx context.Context, call Pager[R], items func(R) []T, opts PageOptions, f func(T) error) (*Cursor, error) {
+	if opts.PageSize > 0 {
+		callMethod(call, "MaxResults", opts.PageSize)
+	}
+	if opts.Start.PageToken != "" {
+		callMethod(call, "PageToken", opts.Start.PageToken)
+	}
+
+	var (
+		count   = 0
+		current = opts.Start.PageToken
+		skip    = opts.Start.Offset
+		next    *Cursor
+	)
+	err := call.Pages(ctx, func(r R) error {
+		list := items(r)
+		token := nextPageToken(r)
+		for i := skip; i < len(list); i++ {
+			if opts.Limit > 0 && count >= opts.Limit {
+				next = cursorAt(current, i, len(list), token)
+				return ErrStop
+			}
+			err := f(list[i])
+			if err == ErrStop {
+				next = cursorAt(current, i+1, len(list), token)
+				return ErrStop
+			}
+			if err != nil {
+				return err
+			}
+			count++
+		}
+		skip = 0
+		current = token
+		if opts.OnPage != nil {
+			var c *Cursor
+			if token != "" {
+				c = &Cursor{PageToken: token}
+			}
+			if err := opts.OnPage(c); err != nil {
+				return err
+			}
+		}
+		if opts.Limit > 0 && count >= opts.Limit && token != "" {
+			next = &Cursor{PageToken: token}
+			return ErrStop
+		}
+		return nil
+	})
+	if err != nil && err != ErrStop {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Collect returns all items of a paged listing, honoring the provided options.
+// See Paginate for details.
+func Collect[R, T any](ctx context.Context, call Pager[R], items func(R) []T, opts PageOptions) ([]T, *Cursor, error) {
+	all := make([]T, 0)
+	next, err := Paginate(ctx, call, items, opts, func(item T) error {
+		all = append(all, item)
+		return nil
+	})
+	return all, next, err
+}
+
+// cursorAt returns the Cursor pointing to the item at offset i of the page
+// with the given token, moving to the next page when i is past the end.
+func cursorAt(token string, i, size int, nextToken string) *Cursor {
+	if i < size {
+		return &Cursor{PageToken: token, Offset: i}
+	}
+	if nextToken == "" {
+		return nil
+	}
+	return &Cursor{PageToken: nextToken}
+}
+
+// nextPageToken returns the NextPageToken field of a response page.
+func nextPageToken(page interface{}) string {
+	v := reflect.Indirect(reflect.ValueOf(page))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName("NextPageToken")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+// callMethod invokes the named single-argument setter on a call, if it exists.
+func callMethod(call interface{}, name string, arg interface{}) {
+	m := reflect.ValueOf(call).MethodByName(name)
+	if !m.IsValid() || m.Type().NumIn() != 1 {
+		return
+	}
+	a := reflect.ValueOf(arg)
+	if !a.Type().ConvertibleTo(m.Type().In(0)) {
+		return
+	}
+	m.Call([]reflect.Value{a.Convert(m.Type().In(0))})
+}
@@ -0,0 +1,258 @@
+package ogle
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+// fakePage mimics a list response of the generated clients.
+type fakePage struct {
+	Items         []int
+	NextPageToken string
+}
+
+// fakeCall mimics a list call of the generated clients, serving the items
+// 0 to total-1.
+type fakeCall struct {
+	total      int
+	maxResults int64
+	pageToken  string
+	fetched    []string
+}
+
+func (c *fakeCall) MaxResults(n int64) *fakeCall {
+	c.maxResults = n
+	return c
+}
+
+func (c *fakeCall) PageToken(token string) *fakeCall {
+	c.pageToken = token
+	return c
+}
+
+func (c *fakeCall) Pages(ctx context.Context, f func(*fakePage) error) error {
+	size := int(c.maxResults)
+	if size == 0 {
+		size = 3
+	}
+	start := 0
+	if c.pageToken != "" {
+		fmt.Sscanf(c.pageToken, "p%d", &start)
+	}
+	for {
+		c.fetched = append(c.fetched, fmt.Sprintf("p%d", start))
+		page := &fakePage{}
+		for i := start; i < start+size && i < c.total; i++ {
+			page.Items = append(page.Items, i)
+		}
+		if start+size < c.total {
+			page.NextPageToken = fmt.Sprintf("p%d", start+size)
+		}
+		if err := f(page); err != nil {
+			return err
+		}
+		if page.NextPageToken == "" {
+			return nil
+		}
+		start += size
+	}
+}
+
+func fakeItems(p *fakePage) []int { return p.Items }
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		opts    PageOptions
+		stopAt  int // item where the callback returns ErrStop, -1 for none
+		items   []int
+		next    *Cursor
+		fetched []string
+		pages   []string
+	}{
+		{
+			name:    "all pages",
+			total:   7,
+			stopAt:  -1,
+			items:   []int{0, 1, 2, 3, 4, 5, 6},
+			fetched: []string{"p0", "p3", "p6"},
+			pages:   []string{"p3", "p6", "end"},
+		},
+		{
+			name:    "page size",
+			total:   5,
+			opts:    PageOptions{PageSize: 2},
+			stopAt:  -1,
+			items:   []int{0, 1, 2, 3, 4},
+			fetched: []string{"p0", "p2", "p4"},
+			pages:   []string{"p2", "p4", "end"},
+		},
+		{
+			name:    "limit within a page",
+			total:   7,
+			opts:    PageOptions{Limit: 4},
+			stopAt:  -1,
+			items:   []int{0, 1, 2, 3},
+			next:    &Cursor{PageToken: "p3", Offset: 1},
+			fetched: []string{"p0", "p3"},
+			pages:   []string{"p3"},
+		},
+		{
+			name:    "limit at a page boundary",
+			total:   7,
+			opts:    PageOptions{Limit: 3},
+			stopAt:  -1,
+			items:   []int{0, 1, 2},
+			next:    &Cursor{PageToken: "p3"},
+			fetched: []string{"p0"},
+			pages:   []string{"p3"},
+		},
+		{
+			name:    "limit past the end",
+			total:   3,
+			opts:    PageOptions{Limit: 3},
+			stopAt:  -1,
+			items:   []int{0, 1, 2},
+			fetched: []string{"p0"},
+			pages:   []string{"end"},
+		},
+		{
+			name:    "stop early",
+			total:   7,
+			stopAt:  4,
+			items:   []int{0, 1, 2, 3, 4},
+			next:    &Cursor{PageToken: "p3", Offset: 2},
+			fetched: []string{"p0", "p3"},
+			pages:   []string{"p3"},
+		},
+		{
+			name:    "stop at the end of a page",
+			total:   7,
+			stopAt:  5,
+			items:   []int{0, 1, 2, 3, 4, 5},
+			next:    &Cursor{PageToken: "p6"},
+			fetched: []string{"p0", "p3"},
+			pages:   []string{"p3"},
+		},
+		{
+			name:    "resume at an offset",
+			total:   7,
+			opts:    PageOptions{Start: Cursor{PageToken: "p3", Offset: 1}},
+			stopAt:  -1,
+			items:   []int{4, 5, 6},
+			fetched: []string{"p3", "p6"},
+			pages:   []string{"p6", "end"},
+		},
+		{
+			name:    "resume with a limit",
+			total:   9,
+			opts:    PageOptions{Start: Cursor{PageToken: "p3", Offset: 2}, Limit: 2},
+			stopAt:  -1,
+			items:   []int{5, 6},
+			next:    &Cursor{PageToken: "p6", Offset: 1},
+			fetched: []string{"p3", "p6"},
+			pages:   []string{"p6"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call := &fakeCall{total: tt.total}
+			var pages []string
+			tt.opts.OnPage = func(next *Cursor) error {
+				if next == nil {
+					pages = append(pages, "end")
+				} else {
+					pages = append(pages, next.PageToken)
+				}
+				return nil
+			}
+			var items []int
+			next, err := Paginate[*fakePage](context.Background(), call, fakeItems, tt.opts, func(i int) error {
+				items = append(items, i)
+				if i == tt.stopAt {
+					return ErrStop
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(items, tt.items) {
+				t.Errorf("items %v, want %v", items, tt.items)
+			}
+			if !reflect.DeepEqual(next, tt.next) {
+				t.Errorf("next %+v, want %+v", next, tt.next)
+			}
+			if !reflect.DeepEqual(call.fetched, tt.fetched) {
+				t.Errorf("fetched pages %v, want %v", call.fetched, tt.fetched)
+			}
+			if !reflect.DeepEqual(pages, tt.pages) {
+				t.Errorf("OnPage cursors %v, want %v", pages, tt.pages)
+			}
+			if tt.opts.PageSize > 0 && call.maxResults != tt.opts.PageSize {
+				t.Errorf("MaxResults %d, want %d", call.maxResults, tt.opts.PageSize)
+			}
+		})
+	}
+}
+
+func TestPaginateErrors(t *testing.T) {
+	boom := errors.New("boom")
+	call := &fakeCall{total: 7}
+	next, err := Paginate[*fakePage](context.Background(), call, fakeItems, PageOptions{}, func(i int) error {
+		if i == 4 {
+			return boom
+		}
+		return nil
+	})
+	if err != boom || next != nil {
+		t.Errorf("callback error: got (%v, %v), want (nil, %v)", next, err, boom)
+	}
+
+	call = &fakeCall{total: 7}
+	opts := PageOptions{OnPage: func(*Cursor) error { return boom }}
+	var items []int
+	_, err = Paginate[*fakePage](context.Background(), call, fakeItems, opts, func(i int) error {
+		items = append(items, i)
+		return nil
+	})
+	if err != boom || len(items) != 3 {
+		t.Errorf("OnPage error: got %v after %v, want %v after the first page", err, items, boom)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	call := &fakeCall{total: 5}
+	items, next, err := Collect[*fakePage](context.Background(), call, fakeItems, PageOptions{Limit: 4, PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(items, want) {
+		t.Errorf("items %v, want %v", items, want)
+	}
+	if want := (&Cursor{PageToken: "p4"}); !reflect.DeepEqual(next, want) {
+		t.Errorf("next %+v, want %+v", next, want)
+	}
+}
+
+func TestCallMethod(t *testing.T) {
+	call := &fakeCall{}
+	callMethod(call, "MaxResults", 25)
+	callMethod(call, "PageToken", "abc")
+	callMethod(call, "Missing", "ignored")
+	callMethod(call, "PageToken", []int{1})
+	if call.maxResults != 25 || call.pageToken != "abc" {
+		t.Errorf("callMethod set (%d, %q), want (25, \"abc\")", call.maxResults, call.pageToken)
+	}
+	if got := nextPageToken(&fakePage{NextPageToken: "t"}); got != "t" {
+		t.Errorf("nextPageToken = %q, want \"t\"", got)
+	}
+	if got := nextPageToken(42); got != "" {
+		t.Errorf("nextPageToken(42) = %q, want \"\"", got)
+	}
+}
@@ -0,0 +1,66 @@
+package ogle
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+// Errors aggregates the errors of a batch of operations. Each position holds
+// the error for the input at the same index, or nil if it succeeded.
+type Errors []error
+
+// Error implements the error interface, reporting every failed operation.
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for i, err := range e {
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("#%d: %v", i, err))
+		}
+	}
+	return fmt.Sprintf("ogle: %d of %d operations failed: %s", len(msgs), len(e), strings.Join(msgs, "; "))
+}
+
+// Parallel calls f for each input using at most workers concurrent goroutines.
+// Results are returned in the same order as the inputs. If any call fails,
+// the returned error is of type Errors.
+//
+// Once the context is cancelled, remaining inputs are not processed and are
+// reported with the context error.
+func Parallel[T, R any](ctx context.Context, workers int, in []T, f func(context.Context, T) (R, error)) ([]R, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]R, len(in))
+	errs := make(Errors, len(in))
+
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i], errs[i] = f(ctx, in[i])
+			}
+		}()
+	}
+	for i := range in {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return results, errs
+		}
+	}
+	return results, nil
+}
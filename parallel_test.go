@@ -0,0 +1,94 @@
+package ogle
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func TestParallel(t *testing.T) {
+	in := []int{0, 1, 2, 3, 4, 5}
+	// Each item waits for the next one to finish, so they complete in
+	// reverse order.
+	done := make([]chan struct{}, len(in)+1)
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	close(done[len(in)])
+	out, err := Parallel(context.Background(), len(in), in, func(ctx context.Context, i int) (string, error) {
+		<-done[i+1]
+		defer close(done[i])
+		if i%3 == 1 {
+			return "", fmt.Errorf("item %d failed", i)
+		}
+		return fmt.Sprintf("item %d", i), nil
+	})
+
+	if want := []string{"item 0", "", "item 2", "item 3", "", "item 5"}; !reflect.DeepEqual(out, want) {
+		t.Errorf("results %q, want %q", out, want)
+	}
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error %v is not of type Errors", err)
+	}
+	for i, e := range errs {
+		if failed := i%3 == 1; failed != (e != nil) {
+			t.Errorf("errs[%d] = %v, want failure %v", i, e, failed)
+		}
+	}
+	want := "ogle: 2 of 6 operations failed: #1: item 1 failed; #4: item 4 failed"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestParallelSuccess(t *testing.T) {
+	var running, peak int32
+	out, err := Parallel(context.Background(), 2, []string{"a", "b", "c", "d", "e"}, func(ctx context.Context, s string) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		return strings.ToUpper(s), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"A", "B", "C", "D", "E"}; !reflect.DeepEqual(out, want) {
+		t.Errorf("results %q, want %q", out, want)
+	}
+	if peak > 2 {
+		t.Errorf("%d calls ran concurrently, want at most 2", peak)
+	}
+}
+
+func TestParallelCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int32
+	_, err := Parallel(ctx, 0, []int{1, 2, 3}, func(ctx context.Context, i int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return i, nil
+	})
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("error %v, want Errors for 3 inputs", err)
+	}
+	for i, e := range errs {
+		if e != context.Canceled {
+			t.Errorf("errs[%d] = %v, want %v", i, e, context.Canceled)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("f called %d times after cancellation", calls)
+	}
+}
@@ -0,0 +1,86 @@
+package ogle
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressBar prints the progress of a long transfer, like an upload. On a
+// terminal the bar is redrawn in place; otherwise a line is printed at most
+// every Interval.
+type ProgressBar struct {
+	mu       sync.Mutex
+	w        io.Writer
+	total    int64
+	width    int
+	start    time.Time
+	last     time.Time
+	terminal bool
+
+	// Interval is the minimum time between lines when w is not a terminal.
+	Interval time.Duration
+}
+
+// NewProgressBar initializes a ProgressBar for a transfer of total bytes.
+func NewProgressBar(w io.Writer, total int64) *ProgressBar {
+	p := &ProgressBar{w: w, total: total, start: time.Now(), Interval: 10 * time.Second}
+	if f, ok := w.(*os.File); ok {
+		p.width = terminalWidth(f)
+		p.terminal = p.width > 0
+	}
+	return p
+}
+
+// Update prints the progress after done bytes were transferred.
+func (p *ProgressBar) Update(done int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	if !p.terminal && done < p.total && now.Sub(p.last) < p.Interval {
+		return
+	}
+	p.last = now
+
+	pct := 100.0
+	if p.total > 0 {
+		pct = float64(done) * 100 / float64(p.total)
+	}
+	status := fmt.Sprintf(" %5.1f%% %s / %s", pct, FormatBytes(uint64(done)), FormatBytes(uint64(p.total)))
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 && done > 0 {
+		rate := float64(done) / elapsed
+		status += fmt.Sprintf(" %s/s", FormatBytes(uint64(rate)))
+		if done < p.total {
+			eta := time.Duration(float64(p.total-done) / rate * float64(time.Second))
+			status += " ETA " + FormatDuration(eta)
+		}
+	}
+	if !p.terminal {
+		fmt.Fprintln(p.w, strings.TrimSpace(status))
+		return
+	}
+
+	// The bar takes the width left by the status, between 10 and 50 columns.
+	bar := p.width - len(status) - 3
+	if bar > 50 {
+		bar = 50
+	}
+	if bar < 10 {
+		fmt.Fprintf(p.w, "\r%s", status)
+		return
+	}
+	filled := int(pct / 100 * float64(bar))
+	fmt.Fprintf(p.w, "\r[%s%s]%s", strings.Repeat("=", filled), strings.Repeat(" ", bar-filled), status)
+}
+
+// Done finishes the progress line.
+func (p *ProgressBar) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.terminal {
+		fmt.Fprintln(p.w)
+	}
+}
@@ -0,0 +1,132 @@
+package ogle
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// RateLimiter is a token bucket that allows up to Burst requests at once and
+// refills at a steady rate of requests per second.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time // replaced in tests
+}
+
+// NewRateLimiter initializes a RateLimiter allowing rps requests per second on
+// average with bursts of up to burst requests. A non-positive rps disables the
+// limit.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Wait blocks until a request is allowed to proceed or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes one token from the bucket, returning zero on success or how
+// long the caller must wait before trying again.
+func (l *RateLimiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = make(map[string]*RateLimiter)
+)
+
+// SetRateLimit configures the RateLimiter shared by all clients of the given
+// API and returns it.
+func SetRateLimit(api string, rps float64, burst int) *RateLimiter {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	l := NewRateLimiter(rps, burst)
+	rateLimiters[api] = l
+	return l
+}
+
+// RateLimiterFor returns the RateLimiter shared by all clients of the given
+// API. If none was configured with SetRateLimit, an unlimited one is returned.
+func RateLimiterFor(api string) *RateLimiter {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	l, ok := rateLimiters[api]
+	if !ok {
+		l = NewRateLimiter(0, 1)
+		rateLimiters[api] = l
+	}
+	return l
+}
+
+// RateLimitTransport is an http.RoundTripper that waits for its Limiter before
+// issuing each request.
+type RateLimitTransport struct {
+	// Base is the transport used to issue the actual requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Limiter controls the rate of outgoing requests.
+	Limiter *RateLimiter
+}
+
+// NewRateLimitTransport initializes a RateLimitTransport that uses the
+// RateLimiter shared by the given API, wrapping the provided base transport.
+func NewRateLimitTransport(api string, base http.RoundTripper) *RateLimitTransport {
+	return &RateLimitTransport{
+		Base:    base,
+		Limiter: RateLimiterFor(api),
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.Limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	if t.Base == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,58 @@
+package ogle
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewRateLimiter(4, 2)
+	l.last, l.now = clock, func() time.Time { return clock }
+
+	steps := []struct {
+		advance time.Duration
+		want    time.Duration
+	}{
+		{0, 0},                      // burst
+		{0, 0},                      // burst
+		{0, 250 * time.Millisecond}, // empty bucket, one token every 250ms
+		{100 * time.Millisecond, 150 * time.Millisecond},
+		{150 * time.Millisecond, 0},
+		{0, 250 * time.Millisecond},
+		{time.Hour, 0}, // refills only up to the burst
+		{0, 0},
+		{0, 250 * time.Millisecond},
+	}
+	for i, step := range steps {
+		clock = clock.Add(step.advance)
+		if got := l.reserve(); got != step.want {
+			t.Errorf("step %d: reserve() = %v, want %v", i, got, step.want)
+		}
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	l := NewRateLimiter(0, 0)
+	for i := 0; i < 100; i++ {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("request %d: reserve() = %v, want 0", i, d)
+		}
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	clock := time.Now()
+	l := NewRateLimiter(0.001, 1)
+	l.last, l.now = clock, func() time.Time { return clock }
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("first Wait: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait with a cancelled context = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,75 @@
+package ogle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SidecarExtensions are the extensions of the metadata files stored next to a
+// media file, like video.mp4.yaml, in order of preference.
+var SidecarExtensions = []string{".yaml", ".yml", ".json"}
+
+// FindSidecar returns the name of the sidecar file of the given media file,
+// or an empty string if there is none.
+func FindSidecar(filename string) string {
+	for _, ext := range SidecarExtensions {
+		if fi, err := os.Stat(filename + ext); err == nil && fi.Mode().IsRegular() {
+			return filename + ext
+		}
+	}
+	return ""
+}
+
+// IsSidecar reports if filename has one of the SidecarExtensions.
+func IsSidecar(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, e := range SidecarExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadSidecar decodes the sidecar file into v, as JSON or YAML depending on
+// its extension.
+func LoadSidecar(filename string, v interface{}) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		err = json.NewDecoder(f).Decode(v)
+	} else {
+		err = DecodeYAML(f, v)
+	}
+	if err != nil {
+		return fmt.Errorf("ogle: invalid sidecar %v: %v", filename, err)
+	}
+	return nil
+}
+
+// SaveSidecar writes v to the sidecar file, as JSON or YAML depending on its
+// extension, replacing it.
+func SaveSidecar(filename string, v interface{}) error {
+	var buf bytes.Buffer
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	} else if err := EncodeYAML(&buf, v); err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
@@ -0,0 +1,191 @@
+package ogle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SubtitleFormats are the caption file formats read and written by
+// ParseSubtitles and WriteSubtitles: SubRip, WebVTT and YouTube SBV.
+var SubtitleFormats = []string{"srt", "vtt", "sbv"}
+
+// Cue is a caption shown from Start to End.
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+
+	// Text is the caption, with one line per line break.
+	Text string
+}
+
+// SubtitleFormat returns the format of the caption file, from its extension.
+func SubtitleFormat(filename string) (string, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	for _, f := range SubtitleFormats {
+		if ext == f {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("ogle: unknown caption format of %v, use one of: %s", filename, strings.Join(SubtitleFormats, ", "))
+}
+
+// cueTiming matches the timing line of SRT and WebVTT cues, with optional
+// hours and WebVTT cue settings after the end time.
+var cueTiming = regexp.MustCompile(`^((?:\d+:)?\d{1,2}:\d{2}[,.]\d{1,3})\s*-->\s*((?:\d+:)?\d{1,2}:\d{2}[,.]\d{1,3})(\s.*)?$`)
+
+// sbvTiming matches the timing line of SBV cues.
+var sbvTiming = regexp.MustCompile(`^(\d+:\d{1,2}:\d{2}\.\d{1,3}),(\d+:\d{1,2}:\d{2}\.\d{1,3})$`)
+
+// ParseSubtitles reads the captions in the given format.
+func ParseSubtitles(r io.Reader, format string) ([]Cue, error) {
+	timing := cueTiming
+	switch format {
+	case "srt", "vtt":
+	case "sbv":
+		timing = sbvTiming
+	default:
+		return nil, fmt.Errorf("ogle: unknown caption format %q, use one of: %s", format, strings.Join(SubtitleFormats, ", "))
+	}
+
+	cues := make([]Cue, 0)
+	s := bufio.NewScanner(r)
+	line := 0
+	var block []string
+	flush := func() error {
+		defer func() { block = block[:0] }()
+		if len(block) == 0 {
+			return nil
+		}
+		start := line - len(block)
+		// Skip the WebVTT header and NOTE, STYLE and REGION blocks.
+		if format == "vtt" {
+			first := block[0]
+			if strings.HasPrefix(first, "WEBVTT") || strings.HasPrefix(first, "NOTE") ||
+				first == "STYLE" || first == "REGION" {
+				return nil
+			}
+		}
+		// The cue number in SRT and the cue identifier in WebVTT are optional
+		// before the timing line.
+		i := 0
+		if format != "sbv" && len(block) > 1 && !timing.MatchString(block[0]) {
+			i = 1
+		}
+		m := timing.FindStringSubmatch(block[i])
+		if m == nil {
+			return fmt.Errorf("ogle: invalid %s caption at line %d: expected timing, got %q", format, start+i, block[i])
+		}
+		c := Cue{Text: strings.Join(block[i+1:], "\n")}
+		var err error
+		if c.Start, err = parseCueTime(m[1]); err == nil {
+			c.End, err = parseCueTime(m[2])
+		}
+		if err != nil {
+			return fmt.Errorf("ogle: invalid %s caption at line %d: %v", format, start+i, err)
+		}
+		cues = append(cues, c)
+		return nil
+	}
+	for s.Scan() {
+		line++
+		text := strings.TrimRight(s.Text(), " \t\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		block = append(block, text)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	line++
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return cues, nil
+}
+
+// parseCueTime parses times like 01:02:03,456, 02:03.456 or 1:02:03.456.
+func parseCueTime(s string) (time.Duration, error) {
+	s = strings.Replace(s, ",", ".", 1)
+	clock, frac, _ := strings.Cut(s, ".")
+	parts := strings.Split(clock, ":")
+	var d time.Duration
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	d *= time.Second
+	// Fractions are milliseconds, padded to three digits.
+	ms, err := strconv.Atoi((frac + "00")[:3])
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return d + time.Duration(ms)*time.Millisecond, nil
+}
+
+// formatCueTime formats d as hours, minutes, seconds and milliseconds, with
+// the given separator before the milliseconds.
+func formatCueTime(d time.Duration, sep string, hourDigits int) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%0*d:%02d:%02d%s%03d", hourDigits, ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// WriteSubtitles writes the captions in the given format.
+func WriteSubtitles(w io.Writer, cues []Cue, format string) error {
+	bw := bufio.NewWriter(w)
+	switch format {
+	case "srt":
+		for i, c := range cues {
+			fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n", i+1,
+				formatCueTime(c.Start, ",", 2), formatCueTime(c.End, ",", 2), c.Text)
+		}
+	case "vtt":
+		bw.WriteString("WEBVTT\n\n")
+		for _, c := range cues {
+			fmt.Fprintf(bw, "%s --> %s\n%s\n\n",
+				formatCueTime(c.Start, ".", 2), formatCueTime(c.End, ".", 2), c.Text)
+		}
+	case "sbv":
+		for _, c := range cues {
+			fmt.Fprintf(bw, "%s,%s\n%s\n\n",
+				formatCueTime(c.Start, ".", 1), formatCueTime(c.End, ".", 1), c.Text)
+		}
+	default:
+		return fmt.Errorf("ogle: unknown caption format %q, use one of: %s", format, strings.Join(SubtitleFormats, ", "))
+	}
+	return bw.Flush()
+}
+
+// ShiftCues moves the captions by offset, which may be negative. Captions
+// that would end before the start of the video are dropped and the ones
+// that would start before it are trimmed.
+func ShiftCues(cues []Cue, offset time.Duration) []Cue {
+	shifted := make([]Cue, 0, len(cues))
+	for _, c := range cues {
+		c.Start += offset
+		c.End += offset
+		if c.End <= 0 {
+			continue
+		}
+		if c.Start < 0 {
+			c.Start = 0
+		}
+		shifted = append(shifted, c)
+	}
+	return shifted
+}
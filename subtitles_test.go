@@ -0,0 +1,206 @@
+package ogle
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func cueAt(start, end string, text string) Cue {
+	s, _ := time.ParseDuration(start)
+	e, _ := time.ParseDuration(end)
+	return Cue{Start: s, End: e, Text: text}
+}
+
+// testCues are written by WriteSubtitles as srtText, vttText and sbvText.
+var testCues = []Cue{
+	cueAt("1s", "2.5s", "Hello"),
+	cueAt("1m2.003s", "1h2m3.45s", "Two\nlines"),
+	cueAt("10h0m0.999s", "10h0m1s", "Olá, <i>mundo</i>"),
+}
+
+const srtText = `1
+00:00:01,000 --> 00:00:02,500
+Hello
+
+2
+00:01:02,003 --> 01:02:03,450
+Two
+lines
+
+3
+10:00:00,999 --> 10:00:01,000
+Olá, <i>mundo</i>
+
+`
+
+const vttText = `WEBVTT
+
+00:00:01.000 --> 00:00:02.500
+Hello
+
+00:01:02.003 --> 01:02:03.450
+Two
+lines
+
+10:00:00.999 --> 10:00:01.000
+Olá, <i>mundo</i>
+
+`
+
+const sbvText = `0:00:01.000,0:00:02.500
+Hello
+
+0:01:02.003,1:02:03.450
+Two
+lines
+
+10:00:00.999,10:00:01.000
+Olá, <i>mundo</i>
+
+`
+
+func TestWriteSubtitles(t *testing.T) {
+	for format, want := range map[string]string{"srt": srtText, "vtt": vttText, "sbv": sbvText} {
+		var buf bytes.Buffer
+		if err := WriteSubtitles(&buf, testCues, format); err != nil {
+			t.Errorf("WriteSubtitles(%s): %v", format, err)
+			continue
+		}
+		if buf.String() != want {
+			t.Errorf("WriteSubtitles(%s):\n%s\nwant:\n%s", format, buf.String(), want)
+		}
+		got, err := ParseSubtitles(&buf, format)
+		if err != nil {
+			t.Errorf("ParseSubtitles(%s): %v", format, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, testCues) {
+			t.Errorf("%s round trip:\n got %q\nwant %q", format, got, testCues)
+		}
+	}
+	if err := WriteSubtitles(&bytes.Buffer{}, testCues, "ass"); err == nil {
+		t.Errorf("WriteSubtitles(ass) succeeded, want an error")
+	}
+}
+
+func TestParseSubtitles(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		text   string
+		want   []Cue
+		err    string
+	}{
+		{
+			name:   "srt with BOM and CRLF",
+			format: "srt",
+			text:   "\ufeff1\r\n00:00:01,000 --> 00:00:02,000\r\nHi\r\n\r\n2\r\n00:00:03,5 --> 00:00:04,25\r\nThere\r\n",
+			want:   []Cue{cueAt("1s", "2s", "Hi"), cueAt("3.5s", "4.25s", "There")},
+		},
+		{
+			name:   "srt without numbers and extra blank lines",
+			format: "srt",
+			text:   "\n\n00:00:01,000 --> 00:00:02,000\nHi\n\n\n\n00:00:03,000 --> 00:00:04,000\nThere",
+			want:   []Cue{cueAt("1s", "2s", "Hi"), cueAt("3s", "4s", "There")},
+		},
+		{
+			name:   "vtt with blocks, identifiers and settings",
+			format: "vtt",
+			text: "WEBVTT - Title\nKind: captions\n\nNOTE a comment\nspanning lines\n\nSTYLE\n::cue { color: red }\n\n" +
+				"intro\n01:02.500 --> 01:03.000 align:start line:0\nHi\n\n00:01:04.000 --> 00:01:05.000\n- A\n- B\n",
+			want: []Cue{cueAt("62.5s", "63s", "Hi"), cueAt("64s", "65s", "- A\n- B")},
+		},
+		{
+			name:   "sbv",
+			format: "sbv",
+			text:   "0:00:01.000,0:00:02.000\nHi\n\n1:00:00.100,1:00:01.000\nThere\n",
+			want:   []Cue{cueAt("1s", "2s", "Hi"), cueAt("1h0m0.1s", "1h0m1s", "There")},
+		},
+		{
+			name:   "empty",
+			format: "vtt",
+			text:   "WEBVTT\n",
+			want:   []Cue{},
+		},
+		{
+			name:   "invalid timing",
+			format: "srt",
+			text:   "1\n00:00:01,000 --> 00:00:02,000\nHi\n\n2\n00:00:03 --> 00:00:04\nThere\n",
+			err:    `invalid srt caption at line 6: expected timing, got "00:00:03 --> 00:00:04"`,
+		},
+		{
+			name:   "srt timing in sbv",
+			format: "sbv",
+			text:   "00:00:01,000 --> 00:00:02,000\nHi\n",
+			err:    "invalid sbv caption at line 1",
+		},
+		{
+			name:   "unknown format",
+			format: "ass",
+			text:   "",
+			err:    `unknown caption format "ass"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSubtitles(strings.NewReader(tt.text), tt.format)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("ParseSubtitles: %v, want error %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSubtitles: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSubtitles:\n got %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShiftCues(t *testing.T) {
+	cues := []Cue{
+		cueAt("1s", "2s", "a"),
+		cueAt("2s", "4s", "b"),
+		cueAt("5s", "6s", "c"),
+	}
+	tests := []struct {
+		offset string
+		want   []Cue
+	}{
+		{"0s", cues},
+		{"1.5s", []Cue{cueAt("2.5s", "3.5s", "a"), cueAt("3.5s", "5.5s", "b"), cueAt("6.5s", "7.5s", "c")}},
+		{"-500ms", []Cue{cueAt("500ms", "1.5s", "a"), cueAt("1.5s", "3.5s", "b"), cueAt("4.5s", "5.5s", "c")}},
+		// Negative offsets clamp the start of a cue at zero, dropping cues
+		// that end at or before it.
+		{"-1.5s", []Cue{cueAt("0s", "500ms", "a"), cueAt("500ms", "2.5s", "b"), cueAt("3.5s", "4.5s", "c")}},
+		{"-1999ms", []Cue{cueAt("0s", "1ms", "a"), cueAt("1ms", "2.001s", "b"), cueAt("3.001s", "4.001s", "c")}},
+		{"-2s", []Cue{cueAt("0s", "2s", "b"), cueAt("3s", "4s", "c")}},
+		{"-3s", []Cue{cueAt("0s", "1s", "b"), cueAt("2s", "3s", "c")}},
+		{"-6s", []Cue{}},
+	}
+	for _, tt := range tests {
+		offset, _ := time.ParseDuration(tt.offset)
+		got := ShiftCues(cues, offset)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ShiftCues(%s):\n got %q\nwant %q", tt.offset, got, tt.want)
+		}
+	}
+	if cues[0] != cueAt("1s", "2s", "a") {
+		t.Errorf("ShiftCues changed its input to %q", cues)
+	}
+}
+
+func TestSubtitleFormat(t *testing.T) {
+	for name, want := range map[string]string{"a.srt": "srt", "dir.x/b.VTT": "vtt", "c.sbv": "sbv", "d.txt": "", "srt": ""} {
+		got, err := SubtitleFormat(name)
+		if got != want || (want == "") != (err != nil) {
+			t.Errorf("SubtitleFormat(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+}
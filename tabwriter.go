@@ -3,22 +3,60 @@ package ogle
 import (
 	"fmt"
 	"io"
+	"os"
+	"reflect"
+	"regexp"
 	"strings"
-	"text/tabwriter"
 )
 
-// TabWriter is a thin wrapper around `text/tabwriter` that prints any
-// interface{} value.
+// TabWriter prints any interface{} value in aligned columns.
+//
+// Unlike `text/tabwriter`, it measures the display width of wide runes, like
+// CJK text and emoji, right-aligns numeric columns and can truncate columns so
+// each line fits in the terminal width. Rows are buffered until Flush is
+// called.
 type TabWriter struct {
-	w *tabwriter.Writer
+	w    io.Writer
+	rows []tabRow
+	cols []tabColumn
+
+	// Width is the maximum width of each line. Text columns are truncated
+	// with an ellipsis to fit. Zero means no limit.
+	Width int
+
+	// Nil is the text printed for nil values.
+	Nil string
+
+	// measured is the number of columns whose width is already set.
+	measured int
+}
+
+type tabRow struct {
+	cells  []string
+	header bool
+}
+
+type tabColumn struct {
+	width     int
+	text      bool // at least one value is not numeric
+	truncate  bool // values can be shortened to fit the line width
+	right     bool // values are always right-aligned
+	fromTags  bool // options were set from struct tags
+	minWidth  int
+	hasValues bool
 }
 
+// minTruncatedWidth is the narrowest a column is truncated to.
+const minTruncatedWidth = 8
+
 // NewTabWriter initializes a TabWriter object with sane defaults shared by all
-// Ogle API clients.
+// Ogle API clients. When w is a terminal, lines are truncated to its width.
 func NewTabWriter(w io.Writer) *TabWriter {
-	return &TabWriter{
-		w: tabwriter.NewWriter(w, 0, 0, 1, ' ', 0),
+	tw := &TabWriter{w: w, Nil: "-"}
+	if f, ok := w.(*os.File); ok {
+		tw.Width = terminalWidth(f)
 	}
+	return tw
 }
 
 // Println prints the provided values in tab separated columns, using the '%v'
@@ -27,15 +65,260 @@ func (tw *TabWriter) Println(args ...interface{}) {
 	if len(args) < 1 {
 		return
 	}
-	f := make([]string, 0, len(args))
-	for i := 0; i < len(args); i++ {
-		f = append(f, "%v")
+	cells := make([]string, 0, len(args))
+	for i, arg := range args {
+		s := tw.format(arg)
+		col := tw.column(i)
+		col.hasValues = true
+		if !isNumeric(arg, s) {
+			col.text = true
+		}
+		cells = append(cells, s)
+	}
+	tw.rows = append(tw.rows, tabRow{cells: cells})
+}
+
+// Header prints the column names. It implements the Formatter interface.
+func (tw *TabWriter) Header(columns ...string) {
+	cells := make([]string, 0, len(columns))
+	for i, c := range columns {
+		tw.column(i)
+		cells = append(cells, clean(c))
+	}
+	tw.rows = append(tw.rows, tabRow{cells: cells, header: true})
+}
+
+// Row prints the column values, ignoring the resource v. It implements the
+// Formatter interface.
+func (tw *TabWriter) Row(v interface{}, values ...interface{}) {
+	tw.Println(values...)
+}
+
+// StructHeader prints the column names for the struct v, taken from the `col`
+// tag of its fields. Only fields with a `col` tag are printed.
+//
+// The tag holds the column name, optionally followed by comma separated
+// options: "truncate" allows the values to be shortened to fit the line width
+// and "right" always right-aligns the values. For example:
+//
+//	type row struct {
+//		ID    string `col:"ID"`
+//		Title string `col:"TITLE,truncate"`
+//		Views uint64 `col:"VIEWS"`
+//	}
+func (tw *TabWriter) StructHeader(v interface{}) {
+	fields := structColumns(reflect.TypeOf(v))
+	names := make([]string, 0, len(fields))
+	for i, f := range fields {
+		tw.setColumnOptions(i, f)
+		names = append(names, f.name)
+	}
+	tw.Header(names...)
+}
+
+// StructRow prints the fields of the struct v that have a `col` tag. See
+// StructHeader for the tag format.
+func (tw *TabWriter) StructRow(v interface{}) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	fields := structColumns(rv.Type())
+	values := make([]interface{}, 0, len(fields))
+	for i, f := range fields {
+		tw.setColumnOptions(i, f)
+		values = append(values, rv.Field(f.index).Interface())
 	}
-	fmt.Fprintf(tw.w, strings.Join(f, "\t")+"\n", args...)
+	tw.Println(values...)
+}
+
+// Close flushes the data. It implements the Formatter interface.
+func (tw *TabWriter) Close() error {
+	return tw.Flush()
 }
 
-// Flush ensures that the data is written to the underlying io.Writer by calling
-// the wrapped tabwriter.Flush corresponding method.
+// Flush writes the buffered rows to the underlying io.Writer, aligning the
+// columns. The column widths are set by the first call, so the rows written
+// by later calls, like the next pages of a listing, stay aligned with the
+// first ones: wider text is truncated, and wider numbers overflow the column.
 func (tw *TabWriter) Flush() error {
-	return tw.w.Flush()
+	if len(tw.rows) == 0 {
+		return nil
+	}
+	for _, row := range tw.rows {
+		for i, cell := range row.cells {
+			if i < tw.measured {
+				continue
+			}
+			if w := StringWidth(cell); w > tw.cols[i].width {
+				tw.cols[i].width = w
+			}
+		}
+	}
+	if tw.measured == 0 {
+		tw.fit()
+	}
+	tw.measured = len(tw.cols)
+
+	var b strings.Builder
+	for _, row := range tw.rows {
+		for i, cell := range row.cells {
+			col := tw.cols[i]
+			if StringWidth(cell) > col.width && col.canTruncate() {
+				cell = Truncate(cell, col.width)
+			}
+			pad := ""
+			if n := col.width - StringWidth(cell); n > 0 {
+				pad = strings.Repeat(" ", n)
+			}
+			last := i == len(row.cells)-1
+			switch {
+			case col.alignRight():
+				b.WriteString(pad + cell)
+			case last:
+				b.WriteString(cell)
+			default:
+				b.WriteString(cell + pad)
+			}
+			if !last {
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	tw.rows = tw.rows[:0]
+	_, err := io.WriteString(tw.w, b.String())
+	return err
+}
+
+// fit shrinks the widest truncatable columns until the lines fit in Width.
+func (tw *TabWriter) fit() {
+	if tw.Width <= 0 {
+		return
+	}
+	total := len(tw.cols) - 1
+	for _, col := range tw.cols {
+		total += col.width
+	}
+	for total > tw.Width {
+		widest := -1
+		for i, col := range tw.cols {
+			if !col.canTruncate() || col.width <= col.minWidth {
+				continue
+			}
+			if widest < 0 || col.width > tw.cols[widest].width {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		tw.cols[widest].width--
+		total--
+	}
+}
+
+func (tw *TabWriter) column(i int) *tabColumn {
+	for len(tw.cols) <= i {
+		tw.cols = append(tw.cols, tabColumn{minWidth: minTruncatedWidth})
+	}
+	return &tw.cols[i]
+}
+
+func (tw *TabWriter) setColumnOptions(i int, f structColumn) {
+	col := tw.column(i)
+	col.fromTags = true
+	col.truncate = f.truncate
+	col.right = f.right
+}
+
+func (c tabColumn) canTruncate() bool {
+	if c.fromTags {
+		return c.truncate
+	}
+	return c.text
+}
+
+func (c tabColumn) alignRight() bool {
+	return c.right || (c.hasValues && !c.text)
+}
+
+// format returns the text printed for v.
+func (tw *TabWriter) format(v interface{}) string {
+	if isNil(v) {
+		return tw.Nil
+	}
+	// Optional API fields are pointers, like *string, printed by their value.
+	switch v.(type) {
+	case fmt.Stringer, error:
+	default:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+			v = rv.Elem().Interface()
+		}
+	}
+	return clean(fmt.Sprintf("%v", v))
+}
+
+var cleaner = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+// clean replaces line breaks and tabs, which would break the alignment.
+func clean(s string) string {
+	return cleaner.Replace(s)
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+var numericText = regexp.MustCompile(`^[-+]?[0-9][0-9.,:]*[KMBT%]?$`)
+
+// isNumeric reports if v, printed as s, is a number.
+func isNumeric(v interface{}, s string) bool {
+	if isNil(v) {
+		return true
+	}
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return numericText.MatchString(s)
+}
+
+type structColumn struct {
+	name     string
+	index    int
+	truncate bool
+	right    bool
+}
+
+// structColumns returns the columns defined by the `col` tags of struct type t.
+func structColumns(t reflect.Type) []structColumn {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	cols := make([]structColumn, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("col")
+		if !ok || tag == "-" {
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		c := structColumn{name: opts[0], index: i}
+		for _, opt := range opts[1:] {
+			switch strings.TrimSpace(opt) {
+			case "truncate":
+				c.truncate = true
+			case "right":
+				c.right = true
+			}
+		}
+		cols = append(cols, c)
+	}
+	return cols
 }
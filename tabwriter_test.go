@@ -0,0 +1,153 @@
+package ogle
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTabWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		rows  [][]interface{}
+		want  string
+	}{
+		{
+			name: "wide runes",
+			rows: [][]interface{}{
+				{"ID", "TITLE", "VIEWS"},
+				{"a1", "日本語のタイトル", 1200},
+				{"b2", "🎬 Live", 5},
+				{"c3", "Title", nil},
+			},
+			want: "" +
+				"ID TITLE            VIEWS\n" +
+				"a1 日本語のタイトル  1200\n" +
+				"b2 🎬 Live              5\n" +
+				"c3 Title                -\n",
+		},
+		{
+			name:  "truncated to the width",
+			width: 20,
+			rows: [][]interface{}{
+				{"ID", "TITLE", "VIEWS"},
+				{"a1", "日本語のタイトルです", 1200},
+				{"b2", "A long title in English", 5},
+			},
+			want: "" +
+				"ID TITLE       VIEWS\n" +
+				"a1 日本語のタ…  1200\n" +
+				"b2 A long tit…     5\n",
+		},
+		{
+			name: "line breaks and tabs",
+			rows: [][]interface{}{
+				{"x", "a\nb\tc", "y"},
+			},
+			want: "x a b c y\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tw := NewTabWriter(&buf)
+			tw.Width = tt.width
+			for i, row := range tt.rows {
+				if i == 0 && len(tt.rows) > 1 {
+					names := make([]string, len(row))
+					for j, v := range row {
+						names[j] = v.(string)
+					}
+					tw.Header(names...)
+					continue
+				}
+				tw.Row(nil, row...)
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTabWriterStruct(t *testing.T) {
+	type row struct {
+		ID      string  `col:"ID"`
+		Title   string  `col:"TITLE,truncate"`
+		Channel string  `col:"CHANNEL"`
+		Code    string  `col:"CODE,right"`
+		Views   uint64  `col:"VIEWS"`
+		Note    *string `col:"NOTE"`
+		Skipped string  `col:"-"`
+		hidden  string
+	}
+	note := "ok"
+	rows := []*row{
+		{ID: "a1", Title: "A very long title here", Channel: "Some channel", Code: "x", Views: 1200, Note: &note},
+		{ID: "b2", Title: "短いタイトル", Channel: "Other", Code: "yz", Views: 5, Skipped: "no", hidden: "no"},
+	}
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{
+			name: "no limit",
+			want: "" +
+				"ID TITLE                  CHANNEL      CODE VIEWS NOTE\n" +
+				"a1 A very long title here Some channel    x  1200 ok\n" +
+				"b2 短いタイトル           Other          yz     5 -\n",
+		},
+		{
+			name:  "only truncate columns shrink",
+			width: 40,
+			want: "" +
+				"ID TITLE    CHANNEL      CODE VIEWS NOTE\n" +
+				"a1 A very … Some channel    x  1200 ok\n" +
+				"b2 短いタ…  Other          yz     5 -\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tw := NewTabWriter(&buf)
+			tw.Width = tt.width
+			tw.StructHeader(row{})
+			for _, r := range rows {
+				tw.StructRow(r)
+			}
+			if err := tw.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestTabWriterKeepsWidthsAcrossFlushes(t *testing.T) {
+	var buf bytes.Buffer
+	tw := NewTabWriter(&buf)
+	tw.Header("#", "TITLE", "VIEWS")
+	tw.Row(nil, 1, "First page", 10)
+	if err := tw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	tw.Row(nil, 2, "A longer title on the second page", 5)
+	tw.Row(nil, 3, "Short", 1234567)
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"# TITLE      VIEWS\n" +
+		"1 First page    10\n" +
+		"2 A longer …     5\n" +
+		"3 Short      1234567\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
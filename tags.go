@@ -0,0 +1,88 @@
+package ogle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxTagsLength is the maximum number of characters used by the tags of a
+// video, as counted by TagsLength. It is checked by VideoMetadata.Validate.
+const MaxTagsLength = 500
+
+// TagsLength returns the number of characters counted by YouTube for the
+// tags: their lengths, plus a comma between tags and two quotes around tags
+// that contain spaces.
+func TagsLength(tags []string) int {
+	n := 0
+	for i, tag := range tags {
+		if i > 0 {
+			n++
+		}
+		n += len([]rune(tag))
+		if strings.Contains(tag, " ") {
+			n += 2
+		}
+	}
+	return n
+}
+
+// ApplyTagEdits changes the tags with a comma separated list of edits. If
+// every edit starts with + or -, the tags are added to or removed from the
+// current ones, ignoring case; otherwise the list replaces them. Duplicated
+// tags are removed, regardless of case, keeping the first one.
+func ApplyTagEdits(tags []string, edits string) ([]string, error) {
+	list := make([]string, 0)
+	incremental := 0
+	for _, e := range strings.Split(edits, ",") {
+		if e = strings.TrimSpace(e); e == "" {
+			continue
+		}
+		if e[0] == '+' || e[0] == '-' {
+			incremental++
+		}
+		list = append(list, e)
+	}
+	if incremental == 0 {
+		return DedupTags(list), nil
+	}
+	if incremental != len(list) {
+		return nil, fmt.Errorf("ogle: tag edits must all start with + or -, or none of them: %q", edits)
+	}
+
+	result := append([]string{}, tags...)
+	for _, e := range list {
+		tag := strings.TrimSpace(e[1:])
+		if tag == "" {
+			continue
+		}
+		if e[0] == '+' {
+			result = append(result, tag)
+			continue
+		}
+		kept := result[:0]
+		for _, t := range result {
+			if !strings.EqualFold(t, tag) {
+				kept = append(kept, t)
+			}
+		}
+		result = kept
+	}
+	return DedupTags(result), nil
+}
+
+// DedupTags removes empty and duplicated tags, ignoring case, keeping the
+// first occurrence of each.
+func DedupTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
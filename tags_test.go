@@ -0,0 +1,138 @@
+package ogle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTagsLength(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"go"}, 2},
+		{[]string{"go", "golang"}, 9},
+		{[]string{"live coding"}, 13},
+		{[]string{"live coding", "go"}, 16},
+		{[]string{"programação"}, 11},
+		{[]string{"日本 語"}, 6},
+		{[]string{"a b c"}, 7},
+	}
+	for _, tt := range tests {
+		if got := TagsLength(tt.tags); got != tt.want {
+			t.Errorf("TagsLength(%q) = %d, want %d", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestApplyTagEdits(t *testing.T) {
+	current := []string{"go", "Live Coding", "youtube"}
+	tests := []struct {
+		name  string
+		tags  []string
+		edits string
+		want  []string
+		err   string
+	}{
+		{
+			name:  "replace",
+			tags:  current,
+			edits: "a, b ,c",
+			want:  []string{"a", "b", "c"},
+		},
+		{
+			name:  "replace removes duplicates ignoring case",
+			tags:  current,
+			edits: "Go,go,GO,golang",
+			want:  []string{"Go", "golang"},
+		},
+		{
+			name:  "replace with nothing",
+			tags:  current,
+			edits: " , ",
+			want:  []string{},
+		},
+		{
+			name:  "add",
+			tags:  current,
+			edits: "+golang,+tutorial",
+			want:  []string{"go", "Live Coding", "youtube", "golang", "tutorial"},
+		},
+		{
+			name:  "add existing tag with different case",
+			tags:  current,
+			edits: "+GO,+live coding",
+			want:  current,
+		},
+		{
+			name:  "remove ignoring case",
+			tags:  current,
+			edits: "-live coding, -YouTube",
+			want:  []string{"go"},
+		},
+		{
+			name:  "remove missing tag",
+			tags:  current,
+			edits: "-java",
+			want:  current,
+		},
+		{
+			name:  "add and remove",
+			tags:  current,
+			edits: "-go,+golang,+Go",
+			want:  []string{"Live Coding", "youtube", "golang", "Go"},
+		},
+		{
+			name:  "add then remove",
+			tags:  current,
+			edits: "+java,-JAVA",
+			want:  current,
+		},
+		{
+			name:  "empty edits are ignored",
+			tags:  current,
+			edits: "+,-, +x",
+			want:  []string{"go", "Live Coding", "youtube", "x"},
+		},
+		{
+			name:  "incremental edits on no tags",
+			edits: "+a,-b",
+			want:  []string{"a"},
+		},
+		{
+			name:  "mixed edits",
+			tags:  current,
+			edits: "+a,b",
+			err:   "must all start with + or -",
+		},
+		{
+			name:  "mixed edits with removal",
+			tags:  current,
+			edits: "a,-go",
+			err:   "must all start with + or -",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := append([]string(nil), tt.tags...)
+			got, err := ApplyTagEdits(tt.tags, tt.edits)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("ApplyTagEdits(%q) = %q, %v, want error %q", tt.edits, got, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyTagEdits(%q): %v", tt.edits, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyTagEdits(%q) = %q, want %q", tt.edits, got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.tags, before) {
+				t.Errorf("ApplyTagEdits(%q) changed the current tags to %q", tt.edits, tt.tags)
+			}
+		})
+	}
+}
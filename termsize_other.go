@@ -0,0 +1,11 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package ogle
+
+import "os"
+
+// terminalWidth returns zero, as the terminal size cannot be detected on this
+// platform.
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,177 @@
+package ogle
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"strings"
+
+	// Formats accepted for thumbnails.
+	_ "image/gif"
+	_ "image/png"
+)
+
+// Thumbnail limits enforced by YouTube for custom thumbnails.
+const (
+	MaxThumbnailSize   = 2 * 1024 * 1024
+	MinThumbnailWidth  = 640
+	ThumbnailWidth     = 1280
+	ThumbnailHeight    = 720
+	thumbnailTolerance = 0.01
+)
+
+// ThumbnailFormats are the image formats accepted for custom thumbnails.
+var ThumbnailFormats = []string{"jpeg", "png", "gif"}
+
+// ThumbnailError lists the reasons an image cannot be used as a thumbnail.
+type ThumbnailError []string
+
+func (e ThumbnailError) Error() string {
+	return "ogle: invalid thumbnail: " + strings.Join(e, "; ")
+}
+
+// ValidateThumbnail checks that the image data can be used as a custom
+// thumbnail: a JPEG, PNG or GIF image of at most 2 MB, with a 16:9 aspect
+// ratio and at least 640 pixels wide. It returns the image format and a
+// ThumbnailError with all problems found, if any.
+func ValidateThumbnail(data []byte) (string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ThumbnailError{fmt.Sprintf("unsupported image format, use one of: %s", strings.Join(ThumbnailFormats, ", "))}
+	}
+	var problems ThumbnailError
+	if len(data) > MaxThumbnailSize {
+		problems = append(problems, fmt.Sprintf("size is %s, the limit is %s", FormatBytes(uint64(len(data))), FormatBytes(MaxThumbnailSize)))
+	}
+	if ratio := float64(cfg.Width) / float64(cfg.Height); math.Abs(ratio-16.0/9.0) > 16.0/9.0*thumbnailTolerance {
+		problems = append(problems, fmt.Sprintf("aspect ratio of %dx%d is not 16:9", cfg.Width, cfg.Height))
+	}
+	if cfg.Width < MinThumbnailWidth {
+		problems = append(problems, fmt.Sprintf("width is %d pixels, the minimum is %d", cfg.Width, MinThumbnailWidth))
+	}
+	if len(problems) > 0 {
+		return format, problems
+	}
+	return format, nil
+}
+
+// FitThumbnail converts the image data into a valid thumbnail: the image is
+// cropped at the center to 16:9, resized to 1280x720 and encoded as JPEG,
+// lowering the quality until it fits in the 2 MB limit.
+func FitThumbnail(data []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("ogle: unable to decode image: %v", err)
+	}
+	return EncodeThumbnail(ResizeImage(CropToRatio(src, 16, 9), ThumbnailWidth, ThumbnailHeight), "jpeg")
+}
+
+// CropToRatio returns the largest centered part of img with the given aspect
+// ratio.
+func CropToRatio(img image.Image, w, h int) image.Image {
+	b := img.Bounds()
+	cw, ch := b.Dx(), b.Dy()
+	if cw*h > ch*w {
+		cw = ch * w / h
+	} else {
+		ch = cw * h / w
+	}
+	r := image.Rect(0, 0, cw, ch).Add(b.Min).Add(image.Pt((b.Dx()-cw)/2, (b.Dy()-ch)/2))
+	rgba := toRGBA(img)
+	return rgba.SubImage(r)
+}
+
+// ResizeImage scales img to width by height pixels. Each destination pixel is
+// the average of the source pixels it covers, or a bilinear interpolation of
+// the nearest ones when enlarging.
+func ResizeImage(img image.Image, width, height int) *image.RGBA {
+	src := toRGBA(img)
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sx := float64(b.Dx()) / float64(width)
+	sy := float64(b.Dy()) / float64(height)
+	for y := 0; y < height; y++ {
+		y0, y1 := float64(y)*sy, float64(y+1)*sy
+		for x := 0; x < width; x++ {
+			x0, x1 := float64(x)*sx, float64(x+1)*sx
+			var c [4]float64
+			if sx <= 1 && sy <= 1 {
+				c = bilinear(src, (x0+x1)/2-0.5, (y0+y1)/2-0.5)
+			} else {
+				c = average(src, x0, y0, x1, y1)
+			}
+			i := dst.PixOffset(x, y)
+			for k := 0; k < 4; k++ {
+				dst.Pix[i+k] = uint8(math.Round(math.Max(0, math.Min(255, c[k]))))
+			}
+		}
+	}
+	return dst
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
+
+// pixel returns the color at x, y relative to the image bounds, clamped to
+// the edges.
+func pixel(img *image.RGBA, x, y int) [4]float64 {
+	b := img.Bounds()
+	x = clampInt(x, 0, b.Dx()-1) + b.Min.X
+	y = clampInt(y, 0, b.Dy()-1) + b.Min.Y
+	i := img.PixOffset(x, y)
+	p := img.Pix[i : i+4]
+	return [4]float64{float64(p[0]), float64(p[1]), float64(p[2]), float64(p[3])}
+}
+
+func bilinear(img *image.RGBA, x, y float64) [4]float64 {
+	fx, fy := math.Floor(x), math.Floor(y)
+	dx, dy := x-fx, y-fy
+	ix, iy := int(fx), int(fy)
+	p00, p10 := pixel(img, ix, iy), pixel(img, ix+1, iy)
+	p01, p11 := pixel(img, ix, iy+1), pixel(img, ix+1, iy+1)
+	var c [4]float64
+	for k := range c {
+		c[k] = p00[k]*(1-dx)*(1-dy) + p10[k]*dx*(1-dy) + p01[k]*(1-dx)*dy + p11[k]*dx*dy
+	}
+	return c
+}
+
+// average returns the mean color of the area from x0, y0 to x1, y1, weighting
+// the pixels partially covered by the area.
+func average(img *image.RGBA, x0, y0, x1, y1 float64) [4]float64 {
+	var c [4]float64
+	var total float64
+	for y := int(y0); float64(y) < y1; y++ {
+		wy := math.Min(y1, float64(y+1)) - math.Max(y0, float64(y))
+		for x := int(x0); float64(x) < x1; x++ {
+			wx := math.Min(x1, float64(x+1)) - math.Max(x0, float64(x))
+			p := pixel(img, x, y)
+			for k := range c {
+				c[k] += p[k] * wx * wy
+			}
+			total += wx * wy
+		}
+	}
+	for k := range c {
+		c[k] /= total
+	}
+	return c
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
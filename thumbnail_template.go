@@ -0,0 +1,351 @@
+package ogle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// ThumbnailTemplate describes how to render a thumbnail: a background image
+// or color, covered by text and image layers drawn in order.
+type ThumbnailTemplate struct {
+	// Background is the image file drawn first, cropped to 16:9 and resized
+	// to 1280x720, or a color like "#202020".
+	Background string `json:"background"`
+
+	// Layers are drawn over the background, in order.
+	Layers []ThumbnailLayer `json:"layers"`
+
+	// dir is the directory used to resolve relative file names.
+	dir string
+}
+
+// ThumbnailLayer is a text or image drawn in a thumbnail. Positions are in
+// pixels from the top left corner of the 1280x720 thumbnail.
+type ThumbnailLayer struct {
+	// Text is a text/template rendered with the template variables, like
+	// "Episode #{{.episode}}".
+	Text string `json:"text,omitempty"`
+
+	// Font is a TrueType or OpenType font file. Go Bold is used if empty.
+	Font string `json:"font,omitempty"`
+
+	// Size is the font size, in pixels. Defaults to 72.
+	Size float64 `json:"size,omitempty"`
+
+	// Color is the text color, like "#ffffff" or "#ffffff80". Defaults to
+	// white.
+	Color string `json:"color,omitempty"`
+
+	// Outline is the width, in pixels, of the outline drawn around the text
+	// with OutlineColor, black by default.
+	Outline      int    `json:"outline,omitempty"`
+	OutlineColor string `json:"outlineColor,omitempty"`
+
+	// Align is how the text lines are placed relative to X: left, center or
+	// right.
+	Align string `json:"align,omitempty"`
+
+	// MaxWidth wraps the text in lines of up to MaxWidth pixels.
+	MaxWidth int `json:"maxWidth,omitempty"`
+
+	// LineHeight is the distance between lines, relative to the font size.
+	// Defaults to 1.2.
+	LineHeight float64 `json:"lineHeight,omitempty"`
+
+	// Image is an image file drawn at X, Y, resized to Width by Height. If
+	// only one of them is set, the aspect ratio is kept.
+	Image  string `json:"image,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+
+	// Opacity of the image, from 0 to 1. Defaults to 1.
+	Opacity *float64 `json:"opacity,omitempty"`
+
+	// X and Y are the position of the top left corner of the layer, or the
+	// top center or right when the text is aligned.
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// LoadThumbnailTemplate reads a template from a JSON or YAML file, depending
+// on its extension. File names in the template are relative to its directory.
+func LoadThumbnailTemplate(filename string) (*ThumbnailTemplate, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	t := &ThumbnailTemplate{dir: filepath.Dir(filename)}
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		err = json.Unmarshal(b, t)
+	} else {
+		err = DecodeYAML(bytes.NewReader(b), t)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ogle: invalid thumbnail template %v: %v", filename, err)
+	}
+	for i, l := range t.Layers {
+		if l.Opacity != nil && !(*l.Opacity >= 0 && *l.Opacity <= 1) {
+			return nil, fmt.Errorf("ogle: invalid thumbnail template %v: layer %d has opacity %v, use a value from 0 to 1", filename, i+1, *l.Opacity)
+		}
+	}
+	return t, nil
+}
+
+func (t *ThumbnailTemplate) path(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(t.dir, name)
+}
+
+// Render draws the thumbnail, replacing the variables in the text layers.
+func (t *ThumbnailTemplate) Render(vars map[string]string) (*image.RGBA, error) {
+	bounds := image.Rect(0, 0, ThumbnailWidth, ThumbnailHeight)
+	dst := image.NewRGBA(bounds)
+	switch {
+	case strings.HasPrefix(t.Background, "#"):
+		c, err := ParseColor(t.Background)
+		if err != nil {
+			return nil, err
+		}
+		draw.Draw(dst, bounds, image.NewUniform(c), image.Point{}, draw.Src)
+	case t.Background != "":
+		bg, err := loadImage(t.path(t.Background))
+		if err != nil {
+			return nil, err
+		}
+		draw.Draw(dst, bounds, ResizeImage(CropToRatio(bg, 16, 9), ThumbnailWidth, ThumbnailHeight), image.Point{}, draw.Src)
+	}
+
+	fonts := make(map[string]*opentype.Font)
+	for i, l := range t.Layers {
+		var err error
+		switch {
+		case l.Text != "" && l.Image != "":
+			err = fmt.Errorf("text and image are both set")
+		case l.Text != "":
+			err = t.drawText(dst, l, vars, fonts)
+		case l.Image != "":
+			err = t.drawImage(dst, l)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ogle: layer %d: %v", i+1, err)
+		}
+	}
+	return dst, nil
+}
+
+func (t *ThumbnailTemplate) drawImage(dst *image.RGBA, l ThumbnailLayer) error {
+	img, err := loadImage(t.path(l.Image))
+	if err != nil {
+		return err
+	}
+	b := img.Bounds()
+	w, h := l.Width, l.Height
+	switch {
+	case w == 0 && h == 0:
+		w, h = b.Dx(), b.Dy()
+	case w == 0:
+		w = b.Dx() * h / b.Dy()
+	case h == 0:
+		h = b.Dy() * w / b.Dx()
+	}
+	if w != b.Dx() || h != b.Dy() {
+		img = ResizeImage(img, w, h)
+	}
+	opacity := 1.0
+	if l.Opacity != nil {
+		opacity = *l.Opacity
+	}
+	mask := image.NewUniform(color.Alpha{uint8(255 * opacity)})
+	r := image.Rect(l.X, l.Y, l.X+w, l.Y+h)
+	draw.DrawMask(dst, r, img, img.Bounds().Min, mask, image.Point{}, draw.Over)
+	return nil
+}
+
+func (t *ThumbnailTemplate) drawText(dst *image.RGBA, l ThumbnailLayer, vars map[string]string, fonts map[string]*opentype.Font) error {
+	tmpl, err := template.New("text").Option("missingkey=error").Parse(l.Text)
+	if err != nil {
+		return err
+	}
+	var text bytes.Buffer
+	if err := tmpl.Execute(&text, vars); err != nil {
+		return err
+	}
+
+	f, ok := fonts[l.Font]
+	if !ok {
+		data := gobold.TTF
+		if l.Font != "" {
+			if data, err = os.ReadFile(t.path(l.Font)); err != nil {
+				return err
+			}
+		}
+		if f, err = opentype.Parse(data); err != nil {
+			return fmt.Errorf("invalid font %v: %v", l.Font, err)
+		}
+		fonts[l.Font] = f
+	}
+	size := l.Size
+	if size == 0 {
+		size = 72
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	fill, err := parseColorDefault(l.Color, color.RGBA{255, 255, 255, 255})
+	if err != nil {
+		return err
+	}
+	outline, err := parseColorDefault(l.OutlineColor, color.RGBA{0, 0, 0, 255})
+	if err != nil {
+		return err
+	}
+	lineHeight := l.LineHeight
+	if lineHeight == 0 {
+		lineHeight = 1.2
+	}
+
+	d := &font.Drawer{Dst: dst, Face: face}
+	ascent := face.Metrics().Ascent.Round()
+	for i, line := range wrapText(d, text.String(), l.MaxWidth) {
+		x := l.X
+		switch l.Align {
+		case "center":
+			x -= d.MeasureString(line).Round() / 2
+		case "right":
+			x -= d.MeasureString(line).Round()
+		case "", "left":
+		default:
+			return fmt.Errorf("invalid align %q, use left, center or right", l.Align)
+		}
+		y := l.Y + ascent + int(float64(i)*size*lineHeight)
+		if l.Outline > 0 {
+			d.Src = image.NewUniform(outline)
+			for dy := -l.Outline; dy <= l.Outline; dy++ {
+				for dx := -l.Outline; dx <= l.Outline; dx++ {
+					if dx*dx+dy*dy > l.Outline*l.Outline {
+						continue
+					}
+					d.Dot = fixed.P(x+dx, y+dy)
+					d.DrawString(line)
+				}
+			}
+		}
+		d.Src = image.NewUniform(fill)
+		d.Dot = fixed.P(x, y)
+		d.DrawString(line)
+	}
+	return nil
+}
+
+// wrapText splits the text in lines, breaking at spaces so that each line is
+// at most width pixels wide. Explicit line breaks are kept.
+func wrapText(d *font.Drawer, text string, width int) []string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if width <= 0 || len(words) == 0 {
+			lines = append(lines, paragraph)
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if d.MeasureString(line+" "+word).Round() > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func loadImage(filename string) (image.Image, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode image %v: %v", filename, err)
+	}
+	return img, nil
+}
+
+// ParseColor parses a color in the #rgb, #rrggbb or #rrggbbaa forms.
+func ParseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 || !strings.HasPrefix(s, "#") {
+		return color.RGBA{}, fmt.Errorf("ogle: invalid color %q, use #rrggbb", s)
+	}
+	// Colors are stored premultiplied by alpha.
+	a := uint32(v & 0xff)
+	pre := func(c uint32) uint8 { return uint8(c * a / 0xff) }
+	return color.RGBA{pre(uint32(v >> 24)), pre(uint32(v >> 16 & 0xff)), pre(uint32(v >> 8 & 0xff)), uint8(a)}, nil
+}
+
+func parseColorDefault(s string, def color.RGBA) (color.RGBA, error) {
+	if s == "" {
+		return def, nil
+	}
+	return ParseColor(s)
+}
+
+// EncodeThumbnail encodes the image as "jpeg" or "png". JPEG images are
+// encoded with the best quality that fits the thumbnail size limit.
+func EncodeThumbnail(img image.Image, format string) ([]byte, error) {
+	switch format {
+	case "jpeg", "jpg":
+		for quality := 95; quality > 0; quality -= 10 {
+			var buf bytes.Buffer
+			if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+				return nil, err
+			}
+			if buf.Len() <= MaxThumbnailSize {
+				return buf.Bytes(), nil
+			}
+		}
+		return nil, fmt.Errorf("ogle: unable to encode the thumbnail in %s", FormatBytes(MaxThumbnailSize))
+	case "png":
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		if buf.Len() > MaxThumbnailSize {
+			return nil, fmt.Errorf("ogle: thumbnail has %s as PNG, over the %s limit; use JPEG instead",
+				FormatBytes(uint64(buf.Len())), FormatBytes(MaxThumbnailSize))
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("ogle: invalid thumbnail format %q, use jpeg or png", format)
+}
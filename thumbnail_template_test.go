@@ -0,0 +1,39 @@
+package ogle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadThumbnailTemplateOpacity(t *testing.T) {
+	tests := []struct {
+		opacity string
+		err     string
+	}{
+		{opacity: "0"},
+		{opacity: "0.5"},
+		{opacity: "1"},
+		{opacity: "1.5", err: "layer 2 has opacity 1.5, use a value from 0 to 1"},
+		{opacity: "-0.1", err: "layer 2 has opacity -0.1, use a value from 0 to 1"},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		filename := filepath.Join(dir, "template.yaml")
+		text := "background: \"#000000\"\nlayers:\n  - text: Title\n  - image: logo.png\n    opacity: " + tt.opacity + "\n"
+		if err := os.WriteFile(filename, []byte(text), 0600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadThumbnailTemplate(filename)
+		if tt.err == "" {
+			if err != nil {
+				t.Errorf("opacity %s: %v", tt.opacity, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("opacity %s: %v, want error %q", tt.opacity, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package ogle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/googleapi"
+)
+
+// DefaultChunkSize is the amount of data sent per request by ResumableUpload.
+const DefaultChunkSize = 8 * 1024 * 1024
+
+// chunkAlign is the granularity required for chunk sizes by Google APIs.
+const chunkAlign = 256 * 1024
+
+// maxUploadRetries is how many times a failed chunk is retried.
+const maxUploadRetries = 5
+
+// uploadBackoff is how long to wait before the given retry of a failed chunk.
+var uploadBackoff = func(retry int) time.Duration {
+	return time.Duration(1<<retry) * time.Second
+}
+
+// ErrSessionExpired is returned by ResumableUpload when the upload session is
+// no longer valid and the upload must start over.
+var ErrSessionExpired = errors.New("ogle: upload session expired")
+
+// ResumableUpload sends a file to a Google API with the resumable upload
+// protocol. The file is sent in chunks and, if the transfer is interrupted,
+// it can be continued later from the last byte received by the server using
+// the session URI.
+type ResumableUpload struct {
+	// Client is the authorized client used to issue the requests.
+	Client *http.Client
+
+	// URL is the upload endpoint, including the query parameters of the
+	// call, like "https://youtube.googleapis.com/upload/youtube/v3/videos?part=snippet".
+	URL string
+
+	// Metadata is the resource sent, encoded as JSON, when the session is
+	// created.
+	Metadata interface{}
+
+	// Media is the content being uploaded, with Size bytes.
+	Media io.ReaderAt
+	Size  int64
+
+	// ContentType is the media type of the content, like "video/mp4".
+	ContentType string
+
+	// SessionURI identifies an upload session. If set, the upload continues
+	// that session instead of creating a new one.
+	SessionURI string
+
+	// ChunkSize is the size of each request. It is rounded to a multiple of
+	// 256 KiB. If zero, DefaultChunkSize is used.
+	ChunkSize int64
+
+	// OnSession is called with the session URI once it is created, so it can
+	// be saved to resume the upload later.
+	OnSession func(uri string) error
+
+	// Progress is called after each chunk with the number of bytes already
+	// received by the server.
+	Progress func(sent int64)
+}
+
+// Do performs the upload, returning the response body of the final request,
+// which holds the created resource.
+func (u *ResumableUpload) Do(ctx context.Context) ([]byte, error) {
+	chunk := u.ChunkSize
+	if chunk <= 0 {
+		chunk = DefaultChunkSize
+	}
+	if chunk = chunk / chunkAlign * chunkAlign; chunk == 0 {
+		chunk = chunkAlign
+	}
+
+	var (
+		offset int64
+		body   []byte
+		err    error
+	)
+	if u.SessionURI == "" {
+		if err := u.start(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		offset, body, err = u.status(ctx)
+	}
+	for retries := 0; ; {
+		if err == nil && body != nil {
+			return body, nil
+		}
+		if err == nil {
+			if u.Progress != nil {
+				u.Progress(offset)
+			}
+			offset, body, err = u.send(ctx, offset, chunk)
+			if err == nil {
+				retries = 0
+			}
+			continue
+		}
+		if !retryable(err) || retries >= maxUploadRetries {
+			return nil, err
+		}
+		retries++
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(uploadBackoff(retries)):
+		}
+		// Ask the server what was received before sending more data.
+		offset, body, err = u.status(ctx)
+	}
+}
+
+// start creates the upload session.
+func (u *ResumableUpload) start(ctx context.Context) error {
+	meta, err := json.Marshal(u.Metadata)
+	if err != nil {
+		return err
+	}
+	sep := "?"
+	if strings.Contains(u.URL, "?") {
+		sep = "&"
+	}
+	req, err := http.NewRequest("POST", u.URL+sep+"uploadType=resumable", bytes.NewReader(meta))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Type", u.ContentType)
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(u.Size, 10))
+	resp, err := u.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return err
+	}
+	if u.SessionURI = resp.Header.Get("Location"); u.SessionURI == "" {
+		return fmt.Errorf("ogle: upload session was not created")
+	}
+	if u.OnSession != nil {
+		return u.OnSession(u.SessionURI)
+	}
+	return nil
+}
+
+// status asks the server how many bytes were received.
+func (u *ResumableUpload) status(ctx context.Context) (int64, []byte, error) {
+	req, err := http.NewRequest("PUT", u.SessionURI, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", u.Size))
+	return u.do(req.WithContext(ctx))
+}
+
+// send uploads the chunk that starts at offset.
+func (u *ResumableUpload) send(ctx context.Context, offset, chunk int64) (int64, []byte, error) {
+	n := chunk
+	if offset+n > u.Size {
+		n = u.Size - offset
+	}
+	req, err := http.NewRequest("PUT", u.SessionURI, io.NewSectionReader(u.Media, offset, n))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.ContentLength = n
+	if n > 0 {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+n-1, u.Size))
+	} else {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", u.Size))
+	}
+	return u.do(req.WithContext(ctx))
+}
+
+// do issues an upload request, returning the offset of the next byte to send
+// or, when the upload is complete, the response body.
+func (u *ResumableUpload) do(req *http.Request) (int64, []byte, error) {
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+		body, err := io.ReadAll(resp.Body)
+		return u.Size, body, err
+	case resp.StatusCode == 308:
+		// Range is "bytes=0-N" with the last byte received, if any.
+		r := resp.Header.Get("Range")
+		if r == "" {
+			return 0, nil, nil
+		}
+		i := strings.LastIndex(r, "-")
+		last, err := strconv.ParseInt(r[i+1:], 10, 64)
+		if err != nil {
+			return 0, nil, fmt.Errorf("ogle: invalid upload range %q: %v", r, err)
+		}
+		return last + 1, nil, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return 0, nil, ErrSessionExpired
+	}
+	return 0, nil, googleapi.CheckResponse(resp)
+}
+
+// retryable reports if the upload can continue after err.
+func retryable(err error) bool {
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code >= 500
+	}
+	return err != ErrSessionExpired && !errors.Is(err, context.Canceled)
+}
+
+// UploadSession records an upload in progress, so it can be resumed.
+type UploadSession struct {
+	// URI identifies the session on the server.
+	URI string `json:"uri"`
+
+	// File is the absolute name of the file being uploaded.
+	File string `json:"file"`
+
+	// CreatedAt is when the session was created. Sessions expire after a
+	// week.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// uploadSessionTTL is how long an upload session is valid.
+const uploadSessionTTL = 7 * 24 * time.Hour
+
+// UploadSessionFile returns the file name used to store the upload session of
+// the given file. Sessions are tied to the file name, size and modification
+// time, so a changed file is uploaded again, and to the account selected
+// with SetAccount.
+func UploadSessionFile(api, filename string) (string, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%d", abs, fi.Size(), fi.ModTime().UnixNano())))
+	return filepath.Join(accountCacheDir(api), "uploads", hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// LoadUploadSession reads the session saved in filename, returning nil if
+// there is none or it has expired.
+func LoadUploadSession(filename string) (*UploadSession, error) {
+	b, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := new(UploadSession)
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("ogle: invalid upload session %v: %v", filename, err)
+	}
+	if time.Since(s.CreatedAt) > uploadSessionTTL {
+		return nil, RemoveUploadSession(filename)
+	}
+	return s, nil
+}
+
+// SaveUploadSession writes the session to filename.
+func SaveUploadSession(filename string, s *UploadSession) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return fmt.Errorf("ogle: unable to create upload session dir: %v", err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// RemoveUploadSession removes the session file, if it exists.
+func RemoveUploadSession(filename string) error {
+	if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,425 @@
+package ogle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/googleapi"
+)
+
+// uploadFault makes a chunk request fail after storing some of its bytes.
+type uploadFault struct {
+	// stored is how many bytes of the chunk the server keeps.
+	stored int64
+	// status is the response code, or zero to drop the connection.
+	status int
+}
+
+// fakeUploadServer implements the server side of the resumable upload
+// protocol, recording the requests it receives.
+type fakeUploadServer struct {
+	*httptest.Server
+	t *testing.T
+
+	mu       sync.Mutex
+	size     int64
+	data     []byte
+	chunks   int
+	faults   map[int]uploadFault // by chunk request number
+	broken   int                 // status of every PUT, if set
+	expired  bool
+	requests []string
+}
+
+func newFakeUploadServer(t *testing.T, size int64) *fakeUploadServer {
+	s := &fakeUploadServer{t: t, size: size}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeUploadServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == "POST" {
+		s.requests = append(s.requests, "POST")
+		if r.URL.Query().Get("uploadType") != "resumable" {
+			s.t.Errorf("session request without uploadType=resumable: %v", r.URL)
+		}
+		if got := r.Header.Get("X-Upload-Content-Length"); got != strconv.FormatInt(s.size, 10) {
+			s.t.Errorf("X-Upload-Content-Length = %q, want %d", got, s.size)
+		}
+		w.Header().Set("Location", s.URL+"/session")
+		return
+	}
+
+	cr := r.Header.Get("Content-Range")
+	s.requests = append(s.requests, "PUT "+cr)
+	switch {
+	case s.expired:
+		s.fail(w, http.StatusGone)
+		return
+	case s.broken != 0:
+		s.fail(w, s.broken)
+		return
+	case strings.HasPrefix(cr, "bytes */"):
+		s.reply(w)
+		return
+	}
+
+	var start, end, total int64
+	if _, err := fmt.Sscanf(cr, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		s.t.Errorf("invalid Content-Range %q", cr)
+		s.fail(w, http.StatusBadRequest)
+		return
+	}
+	if start != int64(len(s.data)) {
+		s.t.Errorf("chunk starts at %d, server has %d bytes", start, len(s.data))
+	}
+	n := s.chunks
+	s.chunks++
+	if f, ok := s.faults[n]; ok {
+		b, _ := io.ReadAll(io.LimitReader(r.Body, f.stored))
+		s.data = append(s.data, b...)
+		if f.status != 0 {
+			s.fail(w, f.status)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		conn.Close()
+		return
+	}
+	b, _ := io.ReadAll(r.Body)
+	s.data = append(s.data, b...)
+	s.reply(w)
+}
+
+// reply reports the bytes received, or the created resource once the upload
+// is complete.
+func (s *fakeUploadServer) reply(w http.ResponseWriter) {
+	if int64(len(s.data)) == s.size {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"video1"}`))
+		return
+	}
+	if len(s.data) > 0 {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(s.data)-1))
+	}
+	w.WriteHeader(308)
+}
+
+func (s *fakeUploadServer) fail(w http.ResponseWriter, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"error":{"code":%d,"message":"failed"}}`, status)
+}
+
+// recordBackoff replaces the retry delays with no wait, recording the retry
+// numbers.
+func recordBackoff(t *testing.T) *[]int {
+	var retries []int
+	orig := uploadBackoff
+	uploadBackoff = func(retry int) time.Duration {
+		retries = append(retries, retry)
+		return 0
+	}
+	t.Cleanup(func() { uploadBackoff = orig })
+	return &retries
+}
+
+func uploadContent(size int) []byte {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = byte(i * 7)
+	}
+	return b
+}
+
+func TestResumableUpload(t *testing.T) {
+	const size = 3*chunkAlign + 10
+	tests := []struct {
+		name     string
+		faults   map[int]uploadFault
+		requests []string
+		retries  []int
+	}{
+		{
+			name: "complete",
+			requests: []string{
+				"POST",
+				"PUT bytes 0-262143/786442",
+				"PUT bytes 262144-524287/786442",
+				"PUT bytes 524288-786431/786442",
+				"PUT bytes 786432-786441/786442",
+			},
+		},
+		{
+			name:   "server error mid-chunk",
+			faults: map[int]uploadFault{1: {stored: 1000, status: http.StatusServiceUnavailable}},
+			requests: []string{
+				"POST",
+				"PUT bytes 0-262143/786442",
+				"PUT bytes 262144-524287/786442",
+				"PUT bytes */786442",
+				"PUT bytes 263144-525287/786442",
+				"PUT bytes 525288-786441/786442",
+			},
+			retries: []int{1},
+		},
+		{
+			name:   "connection dropped mid-chunk",
+			faults: map[int]uploadFault{0: {stored: 4096}},
+			requests: []string{
+				"POST",
+				"PUT bytes 0-262143/786442",
+				"PUT bytes */786442",
+				"PUT bytes 4096-266239/786442",
+				"PUT bytes 266240-528383/786442",
+				"PUT bytes 528384-786441/786442",
+			},
+			retries: []int{1},
+		},
+		{
+			name: "consecutive failures back off longer",
+			faults: map[int]uploadFault{
+				0: {stored: 0, status: http.StatusInternalServerError},
+				1: {stored: 0, status: http.StatusBadGateway},
+				2: {stored: 100, status: http.StatusServiceUnavailable},
+			},
+			requests: []string{
+				"POST",
+				"PUT bytes 0-262143/786442",
+				"PUT bytes */786442",
+				"PUT bytes 0-262143/786442",
+				"PUT bytes */786442",
+				"PUT bytes 0-262143/786442",
+				"PUT bytes */786442",
+				"PUT bytes 100-262243/786442",
+				"PUT bytes 262244-524387/786442",
+				"PUT bytes 524388-786441/786442",
+			},
+			retries: []int{1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries := recordBackoff(t)
+			srv := newFakeUploadServer(t, size)
+			srv.faults = tt.faults
+			content := uploadContent(size)
+
+			var session string
+			var progress []int64
+			u := &ResumableUpload{
+				Client:      srv.Client(),
+				URL:         srv.URL + "/upload?part=snippet",
+				Metadata:    map[string]string{"title": "test"},
+				Media:       bytes.NewReader(content),
+				Size:        size,
+				ContentType: "video/mp4",
+				ChunkSize:   chunkAlign + 1000,
+				OnSession:   func(uri string) error { session = uri; return nil },
+				Progress:    func(sent int64) { progress = append(progress, sent) },
+			}
+			body, err := u.Do(context.Background())
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			if string(body) != `{"id":"video1"}` {
+				t.Errorf("Do returned %q", body)
+			}
+			if session != srv.URL+"/session" {
+				t.Errorf("OnSession called with %q", session)
+			}
+			if !bytes.Equal(srv.data, content) {
+				t.Errorf("server received %d bytes that differ from the %d sent", len(srv.data), size)
+			}
+			if !reflect.DeepEqual(srv.requests, tt.requests) {
+				t.Errorf("requests:\n got %q\nwant %q", srv.requests, tt.requests)
+			}
+			if !reflect.DeepEqual(*retries, tt.retries) {
+				t.Errorf("retries = %v, want %v", *retries, tt.retries)
+			}
+			if len(progress) == 0 || progress[0] != 0 {
+				t.Errorf("progress = %v, want to start at 0", progress)
+			}
+		})
+	}
+}
+
+func TestResumableUploadResume(t *testing.T) {
+	const size = 2*chunkAlign + 5
+	tests := []struct {
+		name     string
+		received int64
+		requests []string
+	}{
+		{
+			name:     "nothing received",
+			received: 0,
+			requests: []string{
+				"PUT bytes */524293",
+				"PUT bytes 0-262143/524293",
+				"PUT bytes 262144-524287/524293",
+				"PUT bytes 524288-524292/524293",
+			},
+		},
+		{
+			name:     "partial range",
+			received: 300000,
+			requests: []string{
+				"PUT bytes */524293",
+				"PUT bytes 300000-524292/524293",
+			},
+		},
+		{
+			name:     "already complete",
+			received: size,
+			requests: []string{
+				"PUT bytes */524293",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries := recordBackoff(t)
+			srv := newFakeUploadServer(t, size)
+			content := uploadContent(size)
+			srv.data = append([]byte(nil), content[:tt.received]...)
+
+			u := &ResumableUpload{
+				Client:     srv.Client(),
+				URL:        srv.URL + "/upload",
+				Media:      bytes.NewReader(content),
+				Size:       size,
+				SessionURI: srv.URL + "/session",
+				ChunkSize:  chunkAlign,
+				OnSession: func(uri string) error {
+					t.Errorf("OnSession(%q) called when resuming", uri)
+					return nil
+				},
+			}
+			body, err := u.Do(context.Background())
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			if string(body) != `{"id":"video1"}` {
+				t.Errorf("Do returned %q", body)
+			}
+			if !bytes.Equal(srv.data, content) {
+				t.Errorf("server received %d bytes that differ from the %d sent", len(srv.data), size)
+			}
+			if !reflect.DeepEqual(srv.requests, tt.requests) {
+				t.Errorf("requests:\n got %q\nwant %q", srv.requests, tt.requests)
+			}
+			if len(*retries) != 0 {
+				t.Errorf("retries = %v, want none", *retries)
+			}
+		})
+	}
+}
+
+func TestResumableUploadErrors(t *testing.T) {
+	const size = chunkAlign
+	tests := []struct {
+		name     string
+		session  bool
+		expired  bool
+		broken   int
+		faults   map[int]uploadFault
+		code     int
+		requests int
+		retries  []int
+	}{
+		{
+			name:     "expired session on resume",
+			session:  true,
+			expired:  true,
+			requests: 1,
+		},
+		{
+			name:     "retries exhausted",
+			broken:   http.StatusServiceUnavailable,
+			code:     http.StatusServiceUnavailable,
+			requests: 1 + 1 + maxUploadRetries,
+			retries:  []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:     "client error is not retried",
+			faults:   map[int]uploadFault{0: {status: http.StatusForbidden}},
+			code:     http.StatusForbidden,
+			requests: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retries := recordBackoff(t)
+			srv := newFakeUploadServer(t, size)
+			srv.expired = tt.expired
+			srv.broken = tt.broken
+			srv.faults = tt.faults
+
+			u := &ResumableUpload{
+				Client: srv.Client(),
+				URL:    srv.URL + "/upload",
+				Media:  bytes.NewReader(uploadContent(size)),
+				Size:   size,
+			}
+			if tt.session {
+				u.SessionURI = srv.URL + "/session"
+			}
+			_, err := u.Do(context.Background())
+			if tt.code == 0 {
+				if err != ErrSessionExpired {
+					t.Errorf("Do: %v, want ErrSessionExpired", err)
+				}
+			} else {
+				var gerr *googleapi.Error
+				if !errors.As(err, &gerr) || gerr.Code != tt.code {
+					t.Errorf("Do: %v, want HTTP %d", err, tt.code)
+				}
+			}
+			if len(srv.requests) != tt.requests {
+				t.Errorf("server got %d requests, want %d: %q", len(srv.requests), tt.requests, srv.requests)
+			}
+			if !reflect.DeepEqual(*retries, tt.retries) {
+				t.Errorf("retries = %v, want %v", *retries, tt.retries)
+			}
+		})
+	}
+}
+
+func TestResumableUploadCanceled(t *testing.T) {
+	recordBackoff(t)
+	srv := newFakeUploadServer(t, chunkAlign)
+	srv.broken = http.StatusServiceUnavailable
+	ctx, cancel := context.WithCancel(context.Background())
+	uploadBackoff = func(retry int) time.Duration {
+		cancel()
+		return time.Hour
+	}
+	u := &ResumableUpload{
+		Client: srv.Client(),
+		URL:    srv.URL + "/upload",
+		Media:  bytes.NewReader(uploadContent(chunkAlign)),
+		Size:   chunkAlign,
+	}
+	if _, err := u.Do(ctx); err != context.Canceled {
+		t.Errorf("Do: %v, want context.Canceled", err)
+	}
+}
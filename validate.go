@@ -0,0 +1,108 @@
+package ogle
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits of video metadata documented by YouTube.
+const (
+	MaxTitleLength       = 100
+	MaxDescriptionLength = 5000
+)
+
+// Violation is a metadata value that breaks one of YouTube's rules.
+type Violation struct {
+	// Field is the name of the invalid field, like "title" or "tags[2]".
+	Field string `json:"field"`
+
+	// Offset is the position, in characters, of the first invalid
+	// character of the value.
+	Offset int `json:"offset"`
+
+	// Message describes the rule that was broken.
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s at offset %d: %s", v.Field, v.Offset, v.Message)
+}
+
+// ValidationError lists all the violations found in the metadata.
+type ValidationError []Violation
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, v := range e {
+		msgs = append(msgs, v.String())
+	}
+	return "ogle: invalid metadata: " + strings.Join(msgs, "; ")
+}
+
+// VideoMetadata holds the fields of a video or broadcast checked by Validate.
+type VideoMetadata struct {
+	Title       string
+	Description string
+	Tags        []string
+}
+
+// Validate checks the metadata against YouTube's limits before it is sent:
+// titles up to 100 characters, descriptions up to 5000 bytes, tags up to the
+// MaxTagsLength budget, and no angle brackets anywhere. Empty fields are not
+// being changed and are not checked. It returns a ValidationError with every
+// violation found.
+func (m VideoMetadata) Validate() error {
+	var errs ValidationError
+	if m.Title != "" {
+		if n := utf8.RuneCountInString(m.Title); n > MaxTitleLength {
+			errs = append(errs, Violation{"title", MaxTitleLength,
+				fmt.Sprintf("has %d characters, the limit is %d", n, MaxTitleLength)})
+		}
+		errs = append(errs, angleBrackets("title", m.Title)...)
+	}
+	if m.Description != "" {
+		if len(m.Description) > MaxDescriptionLength {
+			errs = append(errs, Violation{"description", byteOffset(m.Description, MaxDescriptionLength),
+				fmt.Sprintf("has %d bytes, the limit is %d", len(m.Description), MaxDescriptionLength)})
+		}
+		errs = append(errs, angleBrackets("description", m.Description)...)
+	}
+	for i, tag := range m.Tags {
+		errs = append(errs, angleBrackets(fmt.Sprintf("tags[%d]", i), tag)...)
+	}
+	if n := TagsLength(m.Tags); n > MaxTagsLength {
+		errs = append(errs, Violation{"tags", MaxTagsLength,
+			fmt.Sprintf("use %d characters, the limit is %d", n, MaxTagsLength)})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// angleBrackets reports each < and > in the value, which YouTube rejects.
+func angleBrackets(field, s string) []Violation {
+	var errs []Violation
+	offset := 0
+	for _, r := range s {
+		if r == '<' || r == '>' {
+			errs = append(errs, Violation{field, offset, fmt.Sprintf("contains %q, which is not allowed", r)})
+		}
+		offset++
+	}
+	return errs
+}
+
+// byteOffset returns the offset, in characters, of the character that
+// crosses the limit of n bytes.
+func byteOffset(s string, n int) int {
+	offset := 0
+	for i, r := range s {
+		if i+utf8.RuneLen(r) > n {
+			break
+		}
+		offset++
+	}
+	return offset
+}
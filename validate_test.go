@@ -0,0 +1,143 @@
+package ogle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVideoMetadataValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		meta VideoMetadata
+		want ValidationError
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "title at the limit in multibyte characters",
+			meta: VideoMetadata{Title: strings.Repeat("é", 99) + "🎬"},
+		},
+		{
+			name: "title over the limit in multibyte characters",
+			meta: VideoMetadata{Title: strings.Repeat("日", 101)},
+			want: ValidationError{{"title", 100, "has 101 characters, the limit is 100"}},
+		},
+		{
+			name: "description at the byte limit",
+			meta: VideoMetadata{Description: strings.Repeat("é", 2500)},
+		},
+		{
+			name: "description over the limit by an ASCII byte",
+			meta: VideoMetadata{Description: strings.Repeat("é", 2500) + "a"},
+			want: ValidationError{{"description", 2500, "has 5001 bytes, the limit is 5000"}},
+		},
+		{
+			name: "description with a character across the limit",
+			meta: VideoMetadata{Description: "a" + strings.Repeat("é", 2500)},
+			want: ValidationError{{"description", 2500, "has 5001 bytes, the limit is 5000"}},
+		},
+		{
+			name: "description over the limit with three byte characters",
+			meta: VideoMetadata{Description: strings.Repeat("日", 1667)},
+			want: ValidationError{{"description", 1666, "has 5001 bytes, the limit is 5000"}},
+		},
+		{
+			name: "angle brackets in the title",
+			meta: VideoMetadata{Title: "a <b> 日本 >"},
+			want: ValidationError{
+				{"title", 2, "contains '<', which is not allowed"},
+				{"title", 4, "contains '>', which is not allowed"},
+				{"title", 9, "contains '>', which is not allowed"},
+			},
+		},
+		{
+			name: "angle brackets after multibyte characters",
+			meta: VideoMetadata{Description: "Olá, 世界 <3"},
+			want: ValidationError{{"description", 8, "contains '<', which is not allowed"}},
+		},
+		{
+			name: "angle brackets in tags",
+			meta: VideoMetadata{Tags: []string{"ok", "a<b", "x>"}},
+			want: ValidationError{
+				{"tags[1]", 1, "contains '<', which is not allowed"},
+				{"tags[2]", 1, "contains '>', which is not allowed"},
+			},
+		},
+		{
+			name: "tags at the budget",
+			meta: VideoMetadata{Tags: append(repeatTags("abcdefghi", 49), "abcdefghij")},
+		},
+		{
+			name: "tags over the budget",
+			meta: VideoMetadata{Tags: append(repeatTags("abcdefghi", 50), "x")},
+			want: ValidationError{{"tags", 500, "use 501 characters, the limit is 500"}},
+		},
+		{
+			name: "multibyte tag at the budget",
+			meta: VideoMetadata{Tags: []string{strings.Repeat("ç", 500)}},
+		},
+		{
+			name: "quoted tag at the budget",
+			meta: VideoMetadata{Tags: []string{strings.Repeat("a", 249) + " " + strings.Repeat("b", 248)}},
+		},
+		{
+			name: "quoted tag over the budget",
+			meta: VideoMetadata{Tags: []string{strings.Repeat("a", 249) + " " + strings.Repeat("b", 249)}},
+			want: ValidationError{{"tags", 500, "use 501 characters, the limit is 500"}},
+		},
+		{
+			name: "every violation is reported",
+			meta: VideoMetadata{
+				Title:       strings.Repeat("t", 100) + ">",
+				Description: "<",
+				Tags:        []string{strings.Repeat("x", 501)},
+			},
+			want: ValidationError{
+				{"title", 100, "has 101 characters, the limit is 100"},
+				{"title", 100, "contains '>', which is not allowed"},
+				{"description", 0, "contains '<', which is not allowed"},
+				{"tags", 500, "use 501 characters, the limit is 500"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.meta.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Validate: %v, want no error", err)
+				}
+				return
+			}
+			got, ok := err.(ValidationError)
+			if !ok {
+				t.Fatalf("Validate: %v, want a ValidationError", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate:\n got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	err := ValidationError{
+		{"title", 100, "has 101 characters, the limit is 100"},
+		{"tags[0]", 3, "contains '<', which is not allowed"},
+	}
+	want := "ogle: invalid metadata: title at offset 100: has 101 characters, the limit is 100; " +
+		"tags[0] at offset 3: contains '<', which is not allowed"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func repeatTags(tag string, n int) []string {
+	tags := make([]string, n)
+	for i := range tags {
+		tags[i] = tag
+	}
+	return tags
+}
@@ -0,0 +1,61 @@
+package ogle
+
+import "testing"
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"programação", 11},
+		{"café", 4},
+		{"日本語", 6},
+		{"한국어", 6},
+		{"ｆｕｌｌ", 8},
+		{"Go 言語", 7},
+		{"🎬", 2},
+		{"🚀 launch", 9},
+		{"\u2764\ufe0f", 1},
+		{"✅ done", 7},
+		{"a\tb", 2},
+		{"zero\u200bwidth", 9},
+	}
+	for _, tt := range tests {
+		if got := StringWidth(tt.s); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"hello", 5, "hello"},
+		{"hello", 10, "hello"},
+		{"hello world", 8, "hello w…"},
+		{"hello", 1, "…"},
+		{"hello", 0, ""},
+		{"hello", -1, ""},
+		{"日本語のタイトル", 7, "日本語…"},
+		{"日本語のタイトル", 8, "日本語…"},
+		{"日本語のタイトル", 9, "日本語の…"},
+		{"🎬🎬🎬", 4, "🎬…"},
+		{"🎬🎬🎬", 6, "🎬🎬🎬"},
+		{"programação ao vivo", 12, "programação…"},
+		{"", 3, ""},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if w := StringWidth(got); tt.width >= 0 && w > tt.width {
+			t.Errorf("Truncate(%q, %d) is %d columns wide", tt.s, tt.width, w)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ogle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EncodeYAML writes v to w as a YAML document. The value is first encoded as
+// JSON, so struct fields are named after their JSON tags, keep their order and
+// empty fields marked with omitempty are left out.
+func EncodeYAML(w io.Writer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, so parsing it as a node keeps the field order and
+	// the type of each value.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow and quoting styles of the JSON input, so the
+// encoder picks the most readable style for each node. Empty collections are
+// kept in flow style, as [] and {}.
+func blockStyle(n *yaml.Node) {
+	if len(n.Content) > 0 || n.Kind == yaml.ScalarNode {
+		n.Style = 0
+	}
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
+
+// DecodeYAML reads a YAML document from r and stores it in v. The document is
+// converted to JSON before being stored, so struct fields are matched by their
+// JSON tags.
+func DecodeYAML(r io.Reader, v interface{}) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	if err := yaml.Unmarshal(b, &data); err != nil {
+		return fmt.Errorf("ogle: invalid YAML: %v", err)
+	}
+	j, err := json.Marshal(jsonValue(data))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(j, v)
+}
+
+// jsonValue converts the mappings with non-string keys decoded by yaml.v3, like
+// "1: one", into maps that can be encoded as JSON objects.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = jsonValue(item)
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = jsonValue(item)
+		}
+		return m
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonValue(item)
+		}
+	}
+	return v
+}
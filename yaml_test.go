@@ -0,0 +1,190 @@
+package ogle
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string // JSON
+	}{
+		{
+			name: "literal clip",
+			yaml: "a: |\n  line 1\n    indented\n  line 3\n\nb: end\n",
+			want: `{"a": "line 1\n  indented\nline 3\n", "b": "end"}`,
+		},
+		{
+			name: "literal strip",
+			yaml: "a: |-\n  x\n  y\n\n\nb: end\n",
+			want: `{"a": "x\ny", "b": "end"}`,
+		},
+		{
+			name: "literal keep",
+			yaml: "a: |+\n  x\n\n\nb: end\n",
+			want: `{"a": "x\n\n\n", "b": "end"}`,
+		},
+		{
+			name: "folded",
+			yaml: "a: >\n  one\n  two\n\n  three\n",
+			want: `{"a": "one two\nthree\n"}`,
+		},
+		{
+			name: "folded strip with paragraphs",
+			yaml: "a: >-\n  one\n\n\n  two\n  three\n",
+			want: `{"a": "one\n\ntwo three"}`,
+		},
+		{
+			name: "block scalar at end of document",
+			yaml: "a:\n  b: |\n    text",
+			want: `{"a": {"b": "text"}}`,
+		},
+		{
+			name: "quoted keys",
+			yaml: "\"key: with colon\": 1\n'it''s': x\n\"#tag\": y\n",
+			want: `{"key: with colon": 1, "it's": "x", "#tag": "y"}`,
+		},
+		{
+			name: "comments",
+			yaml: "# header\ntitle: Episode #42 is a comment\nquoted: \"a # b\" # c\nurl: https://example.com/#frag\nempty: # nothing\n",
+			want: `{"title": "Episode", "quoted": "a # b", "url": "https://example.com/#frag", "empty": null}`,
+		},
+		{
+			name: "sequence of mappings",
+			yaml: "layers:\n  - text: hi\n    size: 10\n  - image: a.png\n    opacity: 0.5\n",
+			want: `{"layers": [{"text": "hi", "size": 10}, {"image": "a.png", "opacity": 0.5}]}`,
+		},
+		{
+			name: "sequence at key indentation",
+			yaml: "tags:\n- a\n- b\nnext: 1\n",
+			want: `{"tags": ["a", "b"], "next": 1}`,
+		},
+		{
+			name: "nested sequences",
+			yaml: "- - a\n  - b\n- c\n",
+			want: `[["a", "b"], "c"]`,
+		},
+		{
+			name: "flow collections with quotes",
+			yaml: "tags: [\"a, b\", 'c''d', e, \"[x]\"]\nm: {a: 1, \"b c\": [x, y], 'd': \"}\"}\nnone: []\n",
+			want: `{"tags": ["a, b", "c'd", "e", "[x]"], "m": {"a": 1, "b c": ["x", "y"], "d": "}"}, "none": []}`,
+		},
+		{
+			name: "scalars",
+			yaml: "n: 1.5\nplus: +1\nneg: -3\nzero: 0\nquoted: \"012\"\ninf: inf\nyes: true\nno: FALSE\nnothing: ~\ncategory: 22\ntime: 18:00\ndate: 2024-06-15T18:00:00Z\n",
+			want: `{"n": 1.5, "plus": 1, "neg": -3, "zero": 0, "quoted": "012", "inf": "inf", "yes": true, "no": false, "nothing": null, "category": 22, "time": "18:00", "date": "2024-06-15T18:00:00Z"}`,
+		},
+		{
+			name: "non-string keys",
+			yaml: "1: one\ntrue: yes\n1.5: x\n",
+			want: `{"1": "one", "true": "yes", "1.5": "x"}`,
+		},
+		{
+			name: "document marker and CRLF",
+			yaml: "---\r\na: 1\r\nb: x\r\n",
+			want: `{"a": 1, "b": "x"}`,
+		},
+		{
+			name: "empty document",
+			yaml: "# only comments\n\n",
+			want: `null`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, want interface{}
+			if err := DecodeYAML(strings.NewReader(tt.yaml), &got); err != nil {
+				t.Fatalf("DecodeYAML: %v", err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("DecodeYAML:\n got %#v\nwant %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{"duplicated key", "a: 1\na: 2\n", `line 2: mapping key "a" already defined`},
+		{"bad indentation", "a: 1\n  b: 2\n", "line 2: mapping values are not allowed"},
+		{"unterminated quote", "a: \"x\n", "found unexpected end of stream"},
+		{"unterminated flow", "a: [x, y\n", "did not find expected ',' or ']'"},
+		{"not a key", "a: 1\njust text\n", "line 2: could not find expected ':'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			err := DecodeYAML(strings.NewReader(tt.yaml), &v)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("DecodeYAML(%q) = %v, want error containing %q", tt.yaml, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	type localization struct {
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+	}
+	type doc struct {
+		Title         string                  `json:"title"`
+		Description   string                  `json:"description"`
+		Tags          []string                `json:"tags"`
+		Category      json.Number             `json:"category"`
+		Draft         bool                    `json:"draft"`
+		Opacity       float64                 `json:"opacity"`
+		Empty         []string                `json:"empty"`
+		Nothing       map[string]string       `json:"nothing"`
+		Null          *string                 `json:"null"`
+		Layers        []map[string]any        `json:"layers"`
+		Nested        [][]string              `json:"nested"`
+		Localizations map[string]localization `json:"localizations"`
+	}
+	in := doc{
+		Title:       "Live coding #42: YAML",
+		Description: "Line 1\n  indented\n\n\"quoted\" and 'single' # not a comment\ttab\n",
+		Tags: []string{"go", "yes", "no", "1", "1.5", "-dash", "? q", "a: b", " lead", "trail ",
+			"", "null", "~", "[x]", "{y}", "a, b", "#tag", "@handle", "é ü 日本", "line\nbreak", `back\slash`},
+		Category: "22",
+		Draft:    true,
+		Opacity:  0.75,
+		Empty:    []string{},
+		Nothing:  map[string]string{},
+		Layers: []map[string]any{
+			{"text": "{{.title}}", "size": 90.0, "color": "#ffffff"},
+			{"image": "logo.png", "opacity": 0.5},
+		},
+		Nested: [][]string{{"a", "b"}, {}, {"c"}},
+		Localizations: map[string]localization{
+			"pt-BR": {Title: "Programando ao vivo: #42"},
+			"en":    {Title: "Live", Description: "multi\nline"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeYAML(&buf, in); err != nil {
+		t.Fatalf("EncodeYAML: %v", err)
+	}
+	var out doc
+	if err := DecodeYAML(bytes.NewReader(buf.Bytes()), &out); err != nil {
+		t.Fatalf("DecodeYAML: %v\n%s", err, buf.String())
+	}
+	want, _ := json.Marshal(in)
+	got, _ := json.Marshal(out)
+	if !bytes.Equal(got, want) {
+		t.Errorf("round trip changed the document:\n got %s\nwant %s\nYAML:\n%s", got, want, buf.String())
+	}
+}
@@ -0,0 +1,184 @@
+package ogle
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/youtube/v3"
+)
+
+// YouTubeRef holds the resources referenced by a YouTube ID or URL. A single
+// URL can reference more than one resource, like a video in a playlist.
+type YouTubeRef struct {
+	// ID is set when the reference is a plain ID, whose kind is unknown.
+	ID string
+
+	VideoID    string
+	PlaylistID string
+	ChannelID  string
+
+	// Handle is a channel handle, like "@GoogleDevelopers".
+	Handle string
+
+	// Username is a legacy channel name, from URLs like /user/name.
+	Username string
+
+	// CustomName is a channel custom URL name, from URLs like /c/name or
+	// youtube.com/name.
+	CustomName string
+}
+
+// youtubeHosts are the host names of YouTube URLs, without the "www." prefix.
+var youtubeHosts = map[string]bool{
+	"youtube.com":          true,
+	"m.youtube.com":        true,
+	"music.youtube.com":    true,
+	"studio.youtube.com":   true,
+	"youtu.be":             true,
+	"youtube-nocookie.com": true,
+}
+
+// ParseYouTubeRef extracts the video, playlist and channel IDs from s, which
+// can be a plain ID, a channel handle like @name or any YouTube URL form:
+//
+//	https://youtu.be/VIDEO_ID
+//	https://www.youtube.com/watch?v=VIDEO_ID&list=PLAYLIST_ID
+//	https://www.youtube.com/shorts/VIDEO_ID
+//	https://www.youtube.com/live/VIDEO_ID
+//	https://www.youtube.com/embed/VIDEO_ID
+//	https://www.youtube.com/playlist?list=PLAYLIST_ID
+//	https://www.youtube.com/channel/CHANNEL_ID
+//	https://www.youtube.com/@handle
+//	https://www.youtube.com/c/name
+//	https://www.youtube.com/user/name
+//	https://studio.youtube.com/video/VIDEO_ID/edit
+//	https://studio.youtube.com/playlist/PLAYLIST_ID/videos
+//	https://studio.youtube.com/channel/CHANNEL_ID
+//
+// The scheme may be omitted.
+func ParseYouTubeRef(s string) (*YouTubeRef, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("ogle: empty YouTube ID or URL")
+	}
+	if strings.HasPrefix(s, "@") {
+		return &YouTubeRef{Handle: s}, nil
+	}
+	if !strings.Contains(s, "/") {
+		return &YouTubeRef{ID: s}, nil
+	}
+
+	raw := s
+	if !strings.Contains(s, "://") {
+		raw = "https://" + s
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("ogle: invalid YouTube URL %q: %v", s, err)
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if !youtubeHosts[host] {
+		return nil, fmt.Errorf("ogle: %q is not a YouTube URL", s)
+	}
+
+	ref := &YouTubeRef{
+		VideoID:    u.Query().Get("v"),
+		PlaylistID: u.Query().Get("list"),
+	}
+	path := strings.Split(strings.Trim(u.Path, "/"), "/")
+	first, second := path[0], ""
+	if len(path) > 1 {
+		second = path[1]
+	}
+
+	switch {
+	case host == "youtu.be":
+		ref.VideoID = first
+	case strings.HasPrefix(first, "@"):
+		ref.Handle = first
+	case first == "shorts", first == "live", first == "embed", first == "v", first == "video":
+		ref.VideoID = second
+	case first == "playlist" && second != "":
+		ref.PlaylistID = second
+	case first == "channel":
+		ref.ChannelID = second
+	case first == "c":
+		ref.CustomName = second
+	case first == "user":
+		ref.Username = second
+	case first == "watch", first == "playlist", first == "":
+		// IDs are in the query string.
+	case len(path) == 1 && host == "youtube.com":
+		ref.CustomName = first
+	}
+
+	if *ref == (YouTubeRef{}) {
+		return nil, fmt.Errorf("ogle: no video, playlist or channel found in %q", s)
+	}
+	return ref, nil
+}
+
+// ParseVideoID returns the video ID referenced by s, a plain ID or a URL.
+func ParseVideoID(s string) (string, error) {
+	ref, err := ParseYouTubeRef(s)
+	if err != nil {
+		return "", err
+	}
+	if id := ref.VideoID + ref.ID; id != "" {
+		return id, nil
+	}
+	return "", fmt.Errorf("ogle: %q does not reference a video", s)
+}
+
+// ParsePlaylistID returns the playlist ID referenced by s, a plain ID or a
+// URL.
+func ParsePlaylistID(s string) (string, error) {
+	ref, err := ParseYouTubeRef(s)
+	if err != nil {
+		return "", err
+	}
+	if id := ref.PlaylistID + ref.ID; id != "" {
+		return id, nil
+	}
+	return "", fmt.Errorf("ogle: %q does not reference a playlist", s)
+}
+
+// ResolveChannelID returns the channel ID referenced by s, a plain ID or a
+// URL. Handles, legacy user names and custom URLs are resolved with the API.
+func ResolveChannelID(ctx context.Context, yt *youtube.Service, s string) (string, error) {
+	ref, err := ParseYouTubeRef(s)
+	if err != nil {
+		return "", err
+	}
+	if id := ref.ChannelID + ref.ID; id != "" {
+		return id, nil
+	}
+
+	// Most custom URLs were converted to handles with the same name.
+	lookups := make([]googleapi.CallOption, 0, 2)
+	switch {
+	case ref.Handle != "":
+		lookups = append(lookups, googleapi.QueryParameter("forHandle", ref.Handle))
+	case ref.Username != "":
+		lookups = append(lookups, googleapi.QueryParameter("forUsername", ref.Username))
+	case ref.CustomName != "":
+		lookups = append(lookups,
+			googleapi.QueryParameter("forHandle", "@"+ref.CustomName),
+			googleapi.QueryParameter("forUsername", ref.CustomName))
+	default:
+		return "", fmt.Errorf("ogle: %q does not reference a channel", s)
+	}
+	for _, lookup := range lookups {
+		resp, err := yt.Channels.List([]string{"id"}).Fields("items(id)").Context(ctx).Do(lookup)
+		if err != nil {
+			return "", err
+		}
+		if len(resp.Items) > 0 {
+			return resp.Items[0].Id, nil
+		}
+	}
+	return "", NewFailure("channelNotFound", fmt.Errorf("ogle: no channel found for %q", s))
+}
@@ -0,0 +1,109 @@
+package ogle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseYouTubeRef(t *testing.T) {
+	tests := []struct {
+		in   string
+		want YouTubeRef
+		err  string
+	}{
+		{in: "dQw4w9WgXcQ", want: YouTubeRef{ID: "dQw4w9WgXcQ"}},
+		{in: "  dQw4w9WgXcQ\n", want: YouTubeRef{ID: "dQw4w9WgXcQ"}},
+		{in: "@GoogleDevelopers", want: YouTubeRef{Handle: "@GoogleDevelopers"}},
+		{in: "https://youtu.be/dQw4w9WgXcQ", want: YouTubeRef{VideoID: "dQw4w9WgXcQ"}},
+		{in: "youtu.be/dQw4w9WgXcQ?t=42", want: YouTubeRef{VideoID: "dQw4w9WgXcQ"}},
+		{in: "https://youtu.be/dQw4w9WgXcQ?list=PL123", want: YouTubeRef{VideoID: "dQw4w9WgXcQ", PlaylistID: "PL123"}},
+		{in: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", want: YouTubeRef{VideoID: "dQw4w9WgXcQ"}},
+		{in: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PL123&index=2", want: YouTubeRef{VideoID: "dQw4w9WgXcQ", PlaylistID: "PL123"}},
+		{in: "http://m.youtube.com/watch?list=PL123&v=dQw4w9WgXcQ", want: YouTubeRef{VideoID: "dQw4w9WgXcQ", PlaylistID: "PL123"}},
+		{in: "music.youtube.com/watch?v=dQw4w9WgXcQ", want: YouTubeRef{VideoID: "dQw4w9WgXcQ"}},
+		{in: "https://WWW.YouTube.com/shorts/abc123", want: YouTubeRef{VideoID: "abc123"}},
+		{in: "https://www.youtube.com/live/abc123?feature=share", want: YouTubeRef{VideoID: "abc123"}},
+		{in: "https://www.youtube-nocookie.com/embed/abc123", want: YouTubeRef{VideoID: "abc123"}},
+		{in: "https://www.youtube.com/playlist?list=PL123", want: YouTubeRef{PlaylistID: "PL123"}},
+		{in: "https://www.youtube.com/channel/UC123", want: YouTubeRef{ChannelID: "UC123"}},
+		{in: "https://www.youtube.com/@handle", want: YouTubeRef{Handle: "@handle"}},
+		{in: "youtube.com/@handle/videos", want: YouTubeRef{Handle: "@handle"}},
+		{in: "https://www.youtube.com/c/GoogleDevelopers", want: YouTubeRef{CustomName: "GoogleDevelopers"}},
+		{in: "https://www.youtube.com/user/GoogleDevelopers", want: YouTubeRef{Username: "GoogleDevelopers"}},
+		{in: "https://www.youtube.com/GoogleDevelopers", want: YouTubeRef{CustomName: "GoogleDevelopers"}},
+		{in: "https://studio.youtube.com/video/abc123/edit", want: YouTubeRef{VideoID: "abc123"}},
+		{in: "https://studio.youtube.com/playlist/PL123/videos", want: YouTubeRef{PlaylistID: "PL123"}},
+		{in: "https://studio.youtube.com/channel/UC123", want: YouTubeRef{ChannelID: "UC123"}},
+		{in: "", err: "empty YouTube ID or URL"},
+		{in: "https://vimeo.com/12345", err: "is not a YouTube URL"},
+		{in: "https://www.youtube.com/", err: "no video, playlist or channel found"},
+		{in: "https://www.youtube.com/watch?feature=share", err: "no video, playlist or channel found"},
+		{in: "https://youtu.be/", err: "no video, playlist or channel found"},
+		{in: "https://studio.youtube.com/", err: "no video, playlist or channel found"},
+	}
+	for _, tt := range tests {
+		got, err := ParseYouTubeRef(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseYouTubeRef(%q) = %+v, %v, want error %q", tt.in, got, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseYouTubeRef(%q): %v", tt.in, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("ParseYouTubeRef(%q) = %+v, want %+v", tt.in, *got, tt.want)
+		}
+	}
+}
+
+func TestParseVideoID(t *testing.T) {
+	tests := []struct {
+		in, want, err string
+	}{
+		{in: "dQw4w9WgXcQ", want: "dQw4w9WgXcQ"},
+		{in: "https://youtu.be/dQw4w9WgXcQ", want: "dQw4w9WgXcQ"},
+		{in: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PL123", want: "dQw4w9WgXcQ"},
+		{in: "https://studio.youtube.com/video/abc123/edit", want: "abc123"},
+		{in: "https://www.youtube.com/playlist?list=PL123", err: "does not reference a video"},
+		{in: "@handle", err: "does not reference a video"},
+		{in: "https://example.com/watch?v=x", err: "is not a YouTube URL"},
+	}
+	for _, tt := range tests {
+		got, err := ParseVideoID(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParseVideoID(%q) = %q, %v, want error %q", tt.in, got, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseVideoID(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParsePlaylistID(t *testing.T) {
+	tests := []struct {
+		in, want, err string
+	}{
+		{in: "PL123", want: "PL123"},
+		{in: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PL123", want: "PL123"},
+		{in: "https://studio.youtube.com/playlist/PL123/videos", want: "PL123"},
+		{in: "https://youtu.be/dQw4w9WgXcQ", err: "does not reference a playlist"},
+	}
+	for _, tt := range tests {
+		got, err := ParsePlaylistID(tt.in)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ParsePlaylistID(%q) = %q, %v, want error %q", tt.in, got, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParsePlaylistID(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}